@@ -2,12 +2,12 @@ package cookie
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/base64"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/DevNewbie1826/webUtil/internal/hmacsign"
 )
 
 // cookieContextKey is an unexported type used as a key for context values.
@@ -48,20 +48,12 @@ type CookieManager struct {
 	SecretKey []byte
 }
 
-// sign generates an HMAC-SHA256 signature for the given value and returns it as a base64 URL-encoded string.
-// sign은 주어진 값(value)을 HMAC-SHA256으로 서명한 후, base64 URL 인코딩된 문자열로 반환합니다.
-func (cm *CookieManager) sign(value string) string {
-	h := hmac.New(sha256.New, cm.SecretKey)
-	h.Write([]byte(value))
-	return base64.URLEncoding.EncodeToString(h.Sum(nil))
-}
-
 // SetCookie creates a signed cookie with the specified name, value, and maxAge, and sets it in the HTTP response.
 // The cookie value is stored in the format "base64-encoded-value|signature".
 // SetCookie는 지정한 이름(name), 값(value), 유효기간(maxAge)을 갖는 서명된 쿠키를 생성하여 HTTP 응답(response)에 설정합니다.
 // 쿠키 값은 "base64로 인코딩된 값|서명" 형식으로 저장됩니다.
 func (cm *CookieManager) SetCookie(w http.ResponseWriter, name, value string, maxAge int) {
-	signature := cm.sign(value)
+	signature := hmacsign.Sign(cm.SecretKey, value, base64.URLEncoding)
 	encodedValue := base64.URLEncoding.EncodeToString([]byte(value))
 	finalValue := encodedValue + "|" + signature
 
@@ -103,8 +95,7 @@ func (cm *CookieManager) ReadCookie(r *http.Request, name string) string {
 
 	// Verify the signature.
 	// 서명을 검증합니다.
-	expectedSignature := cm.sign(value)
-	if !hmac.Equal([]byte(signature), []byte(expectedSignature)) {
+	if !hmacsign.Verify(cm.SecretKey, value, signature, base64.URLEncoding) {
 		return ""
 	}
 
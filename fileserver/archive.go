@@ -0,0 +1,586 @@
+package fileserver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveFS is an http.FileSystem serving the contents of a zip, tar or gzip-compressed tar archive,
+// without unpacking it to disk. Stored zip entries and plain tar entries are read with random access,
+// so Range requests are cheap; deflated zip entries are decompressed as they are streamed and can be
+// sent to clients accepting gzip without recompression. Symbolic links and other special entries are skipped.
+//
+// ArchiveFS는 zip, tar 또는 gzip으로 압축된 tar 아카이브의 내용을 디스크에 풀지 않고 제공하는 http.FileSystem입니다.
+// 저장(store) 방식의 zip 항목과 일반 tar 항목은 임의 접근으로 읽으므로 Range 요청 비용이 낮고, deflate로 압축된
+// zip 항목은 스트리밍하면서 압축을 해제하며 gzip을 허용하는 클라이언트에게는 재압축 없이 전송할 수 있습니다.
+// 심볼릭 링크와 그 밖의 특수 항목은 건너뜁니다.
+type ArchiveFS struct {
+	entries map[string]*archiveEntry
+	closer  io.Closer
+}
+
+// archiveEntry is a file or directory of an archive.
+// archiveEntry는 아카이브의 파일 또는 디렉토리입니다.
+type archiveEntry struct {
+	name     string
+	size     int64
+	mode     fs.FileMode
+	modTime  time.Time
+	children []*archiveEntry
+	// open returns a reader of the content.
+	// open은 콘텐츠의 reader를 반환합니다.
+	open func() (io.ReadSeeker, error)
+	// gzip returns the content as a gzip stream without recompression, or is nil if it is not available.
+	// gzip은 재압축 없이 콘텐츠를 gzip 스트림으로 반환하며, 사용할 수 없으면 nil입니다.
+	gzip func() *io.SectionReader
+}
+
+// defaultMaxUnpackedSize is the default limit of the content of a tar.gz archive unpacked into memory.
+// defaultMaxUnpackedSize는 메모리에 압축 해제되는 tar.gz 아카이브 콘텐츠의 기본 제한입니다.
+const defaultMaxUnpackedSize = 256 << 20
+
+// archiveConfig holds the optional settings of an ArchiveFS.
+// archiveConfig는 ArchiveFS의 선택적 설정을 보관합니다.
+type archiveConfig struct {
+	// maxUnpackedSize limits the content unpacked into memory, or is zero for no limit.
+	// maxUnpackedSize는 메모리에 압축 해제되는 콘텐츠를 제한하며, 0이면 제한이 없습니다.
+	maxUnpackedSize int64
+}
+
+// ArchiveOption configures an ArchiveFS.
+// ArchiveOption은 ArchiveFS를 설정합니다.
+type ArchiveOption func(*archiveConfig)
+
+// WithMaxUnpackedSize limits the total size of the files of a gzip-compressed tar archive, which are unpacked
+// into memory. Larger archives are rejected. Defaults to 256 MiB; zero means no limit.
+//
+// WithMaxUnpackedSize는 메모리에 압축 해제되는 gzip 압축 tar 아카이브의 파일 크기 합계를 제한합니다.
+// 이보다 큰 아카이브는 거부됩니다. 기본값은 256 MiB이며, 0이면 제한이 없습니다.
+func WithMaxUnpackedSize(n int64) ArchiveOption {
+	return func(c *archiveConfig) {
+		c.maxUnpackedSize = n
+	}
+}
+
+// OpenArchive opens the named zip, tar or tar.gz file as an ArchiveFS. The archive format is detected from
+// its content. Close the ArchiveFS to close the file.
+//
+// OpenArchive는 지정된 zip, tar 또는 tar.gz 파일을 ArchiveFS로 엽니다. 아카이브 형식은 내용으로 판별합니다.
+// 파일을 닫으려면 ArchiveFS를 Close하세요.
+func OpenArchive(name string, opts ...ArchiveOption) (*ArchiveFS, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	a, err := NewArchiveFS(f, stat.Size(), opts...)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	a.closer = f
+	return a, nil
+}
+
+// NewArchiveFS creates an ArchiveFS reading a zip, tar or tar.gz archive of the given size from r.
+// The archive format is detected from its content. Gzip-compressed tar archives are decompressed into memory,
+// since they do not allow random access, up to the limit set by WithMaxUnpackedSize.
+// Archives with encrypted zip entries or sparse tar entries are rejected.
+//
+// NewArchiveFS는 r에서 주어진 크기의 zip, tar 또는 tar.gz 아카이브를 읽는 ArchiveFS를 생성합니다.
+// 아카이브 형식은 내용으로 판별합니다. gzip으로 압축된 tar 아카이브는 임의 접근이 불가능하므로
+// WithMaxUnpackedSize로 설정한 제한까지 메모리에 압축을 풉니다.
+// 암호화된 zip 항목이나 희소(sparse) tar 항목이 있는 아카이브는 거부됩니다.
+func NewArchiveFS(r io.ReaderAt, size int64, opts ...ArchiveOption) (*ArchiveFS, error) {
+	cfg := archiveConfig{maxUnpackedSize: defaultMaxUnpackedSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.maxUnpackedSize < 0 {
+		return nil, fmt.Errorf("fileserver: invalid unpacked size limit %d", cfg.maxUnpackedSize)
+	}
+
+	var magic [4]byte
+	n, err := r.ReadAt(magic[:], 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	a := &ArchiveFS{entries: make(map[string]*archiveEntry)}
+	switch {
+	case n >= 4 && (bytes.Equal(magic[:], []byte("PK\x03\x04")) || bytes.Equal(magic[:], []byte("PK\x05\x06"))):
+		err = a.loadZip(r, size)
+	case n >= 2 && magic[0] == 0x1f && magic[1] == 0x8b:
+		err = a.loadTarGz(io.NewSectionReader(r, 0, size), cfg.maxUnpackedSize)
+	default:
+		err = a.loadTar(r, size)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fileserver: reading archive: %w", err)
+	}
+	a.link()
+	return a, nil
+}
+
+// Open opens the named file or directory.
+// Open은 지정된 파일 또는 디렉토리를 엽니다.
+func (a *ArchiveFS) Open(name string) (http.File, error) {
+	e, ok := a.entries[path.Clean("/"+name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	f := &archiveFile{entry: e}
+	if !e.mode.IsDir() {
+		rs, err := e.open()
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		f.ReadSeeker = rs
+	}
+	return f, nil
+}
+
+// Close closes the archive file opened by OpenArchive.
+// Close는 OpenArchive가 연 아카이브 파일을 닫습니다.
+func (a *ArchiveFS) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// add registers a file entry under its cleaned name. Entries escaping the archive root are cleaned into it.
+// add는 파일 항목을 정리된 이름으로 등록합니다. 아카이브 루트를 벗어나는 항목은 루트 안으로 정리됩니다.
+func (a *ArchiveFS) add(name string, e *archiveEntry) {
+	name = path.Clean("/" + name)
+	if name == "/" {
+		return
+	}
+	e.name = path.Base(name)
+	a.entries[name] = e
+}
+
+// link creates the directories missing from the archive and attaches every entry to its parent.
+// link는 아카이브에 없는 디렉토리를 만들고 모든 항목을 상위 디렉토리에 연결합니다.
+func (a *ArchiveFS) link() {
+	if _, ok := a.entries["/"]; !ok {
+		a.entries["/"] = &archiveEntry{name: "/", mode: fs.ModeDir | 0o555}
+	}
+	names := make([]string, 0, len(a.entries))
+	for name := range a.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		e, ok := a.entries[name]
+		if name == "/" || !ok {
+			continue
+		}
+		p := a.parent(name)
+		if p == nil {
+			// A file shadows a directory of the same name; drop the orphan
+			// 같은 이름의 파일이 디렉토리를 가리므로 고아 항목을 제거
+			delete(a.entries, name)
+			continue
+		}
+		p.children = append(p.children, e)
+	}
+	for _, e := range a.entries {
+		sort.Slice(e.children, func(i, j int) bool { return e.children[i].name < e.children[j].name })
+	}
+}
+
+// parent returns the directory containing name, creating missing directories. It returns nil if a file is in the way.
+// parent는 name을 포함하는 디렉토리를 반환하며, 없는 디렉토리는 생성합니다. 중간에 파일이 있으면 nil을 반환합니다.
+func (a *ArchiveFS) parent(name string) *archiveEntry {
+	dir := path.Dir(name)
+	if p, ok := a.entries[dir]; ok {
+		if !p.mode.IsDir() {
+			return nil
+		}
+		return p
+	}
+	grandparent := a.parent(dir)
+	if grandparent == nil {
+		return nil
+	}
+	p := &archiveEntry{name: path.Base(dir), mode: fs.ModeDir | 0o555}
+	a.entries[dir] = p
+	grandparent.children = append(grandparent.children, p)
+	return p
+}
+
+// loadZip indexes the entries of a zip archive.
+// loadZip은 zip 아카이브의 항목을 색인합니다.
+func (a *ArchiveFS) loadZip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if f.Flags&zipFlagEncrypted != 0 {
+			return fmt.Errorf("encrypted entry %q is not supported", f.Name)
+		}
+		mode := f.Mode()
+		if mode.IsDir() {
+			a.add(f.Name, &archiveEntry{mode: fs.ModeDir | mode.Perm(), modTime: f.Modified})
+			continue
+		}
+		if !mode.IsRegular() || f.UncompressedSize64 > math.MaxInt64 {
+			continue
+		}
+		e := &archiveEntry{size: int64(f.UncompressedSize64), mode: mode, modTime: f.Modified}
+		offset, err := f.DataOffset()
+		if err != nil {
+			return err
+		}
+		switch f.Method {
+		case zip.Store:
+			e.open = func() (io.ReadSeeker, error) {
+				return io.NewSectionReader(r, offset, e.size), nil
+			}
+		case zip.Deflate:
+			e.open = func() (io.ReadSeeker, error) {
+				return &inflateSeeker{open: f.Open, size: e.size}, nil
+			}
+			if f.CompressedSize64 <= math.MaxInt64 && f.UncompressedSize64 <= math.MaxUint32 {
+				e.gzip = gzipPassthrough(io.NewSectionReader(r, offset, int64(f.CompressedSize64)), f.CRC32, uint32(f.UncompressedSize64))
+			}
+		default:
+			e.open = func() (io.ReadSeeker, error) {
+				return &inflateSeeker{open: f.Open, size: e.size}, nil
+			}
+		}
+		a.add(f.Name, e)
+	}
+	return nil
+}
+
+// loadTar indexes the entries of an uncompressed tar archive, recording the offset of every file's data.
+// loadTar는 압축되지 않은 tar 아카이브의 항목을 색인하며, 각 파일 데이터의 오프셋을 기록합니다.
+func (a *ArchiveFS) loadTar(r io.ReaderAt, size int64) error {
+	cr := &countingReader{r: io.NewSectionReader(r, 0, size)}
+	tr := tar.NewReader(cr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if isSparse(hdr) {
+			return fmt.Errorf("sparse entry %q is not supported", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			a.add(hdr.Name, &archiveEntry{mode: fs.ModeDir | fs.FileMode(hdr.Mode).Perm(), modTime: hdr.ModTime})
+		case tar.TypeReg:
+			offset, e := cr.n, &archiveEntry{size: hdr.Size, mode: fs.FileMode(hdr.Mode).Perm(), modTime: hdr.ModTime}
+			e.open = func() (io.ReadSeeker, error) {
+				return io.NewSectionReader(r, offset, e.size), nil
+			}
+			a.add(hdr.Name, e)
+		}
+	}
+}
+
+// loadTarGz decompresses a gzip-compressed tar archive into memory, up to limit bytes of file content
+// unless limit is zero.
+// loadTarGz는 gzip으로 압축된 tar 아카이브를 메모리에 압축 해제하며, limit이 0이 아니면 파일 콘텐츠를
+// limit 바이트까지만 해제합니다.
+func (a *ArchiveFS) loadTarGz(r io.Reader, limit int64) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+	var unpacked int64
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if isSparse(hdr) {
+			return fmt.Errorf("sparse entry %q is not supported", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			a.add(hdr.Name, &archiveEntry{mode: fs.ModeDir | fs.FileMode(hdr.Mode).Perm(), modTime: hdr.ModTime})
+		case tar.TypeReg:
+			if limit > 0 && hdr.Size > limit-unpacked {
+				return fmt.Errorf("unpacked content exceeds %d bytes", limit)
+			}
+			unpacked += hdr.Size
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			a.add(hdr.Name, &archiveEntry{
+				size:    int64(len(content)),
+				mode:    fs.FileMode(hdr.Mode).Perm(),
+				modTime: hdr.ModTime,
+				open:    func() (io.ReadSeeker, error) { return bytes.NewReader(content), nil },
+			})
+		}
+	}
+}
+
+// zipFlagEncrypted is the general purpose flag bit marking encrypted zip entries.
+// zipFlagEncrypted는 암호화된 zip 항목을 나타내는 범용 플래그 비트입니다.
+const zipFlagEncrypted = 0x1
+
+// isSparse reports whether hdr is a GNU or PAX sparse file, whose data is not stored contiguously.
+// isSparse는 hdr이 데이터가 연속으로 저장되지 않는 GNU 또는 PAX 희소 파일인지 여부를 반환합니다.
+func isSparse(hdr *tar.Header) bool {
+	if hdr.Typeflag == tar.TypeGNUSparse {
+		return true
+	}
+	for key := range hdr.PAXRecords {
+		if strings.HasPrefix(key, "GNU.sparse.") {
+			return true
+		}
+	}
+	return false
+}
+
+// countingReader counts the bytes read from r, so the offsets of tar entries are known.
+// countingReader는 r에서 읽은 바이트 수를 세어 tar 항목의 오프셋을 알 수 있게 합니다.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read reads from the underlying reader and counts the bytes.
+// Read는 내부 reader에서 읽고 바이트 수를 셉니다.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// gzipPassthrough returns a function producing a gzip stream around raw deflate data, using the CRC-32 and size
+// recorded in the zip archive.
+// gzipPassthrough는 zip 아카이브에 기록된 CRC-32와 크기를 사용하여 원시 deflate 데이터를 감싼 gzip 스트림을
+// 만드는 함수를 반환합니다.
+func gzipPassthrough(raw *io.SectionReader, crc, size uint32) func() *io.SectionReader {
+	header := []byte{0x1f, 0x8b, 8, 0, 0, 0, 0, 0, 0, 255}
+	trailer := binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, crc), size)
+	parts := []io.ReaderAt{bytes.NewReader(header), raw, bytes.NewReader(trailer)}
+	sizes := []int64{int64(len(header)), raw.Size(), int64(len(trailer))}
+	total := sizes[0] + sizes[1] + sizes[2]
+	return func() *io.SectionReader {
+		return io.NewSectionReader(multiReaderAt{parts: parts, sizes: sizes}, 0, total)
+	}
+}
+
+// multiReaderAt concatenates several io.ReaderAt of known sizes.
+// multiReaderAt는 크기를 아는 여러 io.ReaderAt를 이어 붙입니다.
+type multiReaderAt struct {
+	parts []io.ReaderAt
+	sizes []int64
+}
+
+// ReadAt reads len(p) bytes at offset off of the concatenation.
+// ReadAt은 이어 붙인 데이터의 오프셋 off에서 len(p) 바이트를 읽습니다.
+func (m multiReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	read := 0
+	for i, part := range m.parts {
+		if off >= m.sizes[i] {
+			off -= m.sizes[i]
+			continue
+		}
+		want := min(int64(len(p)-read), m.sizes[i]-off)
+		n, err := part.ReadAt(p[read:read+int(want)], off)
+		read += n
+		if err != nil && !(errors.Is(err, io.EOF) && int64(n) == want) {
+			return read, err
+		}
+		off = 0
+		if read == len(p) {
+			return read, nil
+		}
+	}
+	return read, io.EOF
+}
+
+// inflateSeeker makes a decompressing stream seekable: seeking forward skips data,
+// seeking backward reopens the stream. Seeking to the end is free, since the size is known.
+// inflateSeeker는 압축 해제 스트림을 탐색 가능하게 만듭니다. 앞으로 탐색하면 데이터를 건너뛰고,
+// 뒤로 탐색하면 스트림을 다시 엽니다. 크기를 알고 있으므로 끝으로 탐색하는 비용은 없습니다.
+type inflateSeeker struct {
+	open   func() (io.ReadCloser, error)
+	size   int64
+	rc     io.ReadCloser
+	pos    int64
+	target int64
+}
+
+// Read reads decompressed data at the current offset.
+// Read는 현재 오프셋에서 압축 해제된 데이터를 읽습니다.
+func (s *inflateSeeker) Read(p []byte) (int, error) {
+	if s.target >= s.size {
+		return 0, io.EOF
+	}
+	if s.rc == nil || s.target < s.pos {
+		if s.rc != nil {
+			s.rc.Close()
+		}
+		rc, err := s.open()
+		if err != nil {
+			return 0, err
+		}
+		s.rc, s.pos = rc, 0
+	}
+	if s.target > s.pos {
+		n, err := io.CopyN(io.Discard, s.rc, s.target-s.pos)
+		s.pos += n
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err := s.rc.Read(p)
+	s.pos += int64(n)
+	s.target = s.pos
+	return n, err
+}
+
+// Seek sets the offset of the next Read.
+// Seek는 다음 Read의 오프셋을 설정합니다.
+func (s *inflateSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.target
+	case io.SeekEnd:
+		offset += s.size
+	default:
+		return 0, errors.New("fileserver: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("fileserver: negative position")
+	}
+	s.target = offset
+	return offset, nil
+}
+
+// Close closes the decompressing stream.
+// Close는 압축 해제 스트림을 닫습니다.
+func (s *inflateSeeker) Close() error {
+	if s.rc == nil {
+		return nil
+	}
+	return s.rc.Close()
+}
+
+// gzipFile is implemented by files whose content is available as a gzip stream without recompression.
+// gzipFile은 재압축 없이 콘텐츠를 gzip 스트림으로 제공할 수 있는 파일이 구현합니다.
+type gzipFile interface {
+	gzipContent() (*io.SectionReader, bool)
+}
+
+// archiveFile is an opened file or directory of an ArchiveFS.
+// archiveFile은 ArchiveFS에서 열린 파일 또는 디렉토리입니다.
+type archiveFile struct {
+	io.ReadSeeker
+	entry   *archiveEntry
+	readdir int
+}
+
+// Read reads the file content. Reading a directory fails.
+// Read는 파일 내용을 읽습니다. 디렉토리를 읽으면 실패합니다.
+func (f *archiveFile) Read(p []byte) (int, error) {
+	if f.ReadSeeker == nil {
+		return 0, errIsDirectory
+	}
+	return f.ReadSeeker.Read(p)
+}
+
+// Seek sets the offset of the next Read.
+// Seek는 다음 Read의 오프셋을 설정합니다.
+func (f *archiveFile) Seek(offset int64, whence int) (int64, error) {
+	if f.ReadSeeker == nil {
+		return 0, errIsDirectory
+	}
+	return f.ReadSeeker.Seek(offset, whence)
+}
+
+// Close releases the file's decompressing stream, if any.
+// Close는 파일의 압축 해제 스트림이 있으면 해제합니다.
+func (f *archiveFile) Close() error {
+	if c, ok := f.ReadSeeker.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Readdir returns the entries of a directory, following the semantics of os.File.Readdir.
+// Readdir는 os.File.Readdir의 의미에 따라 디렉토리의 항목을 반환합니다.
+func (f *archiveFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.entry.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: f.entry.name, Err: errors.New("not a directory")}
+	}
+	children := f.entry.children[f.readdir:]
+	if count > 0 {
+		if len(children) == 0 {
+			return nil, io.EOF
+		}
+		children = children[:min(count, len(children))]
+	}
+	f.readdir += len(children)
+	infos := make([]os.FileInfo, len(children))
+	for i, child := range children {
+		infos[i] = archiveInfo{child}
+	}
+	return infos, nil
+}
+
+// Stat returns the file info of the entry.
+// Stat은 항목의 파일 정보를 반환합니다.
+func (f *archiveFile) Stat() (os.FileInfo, error) {
+	return archiveInfo{f.entry}, nil
+}
+
+// gzipContent returns the content as a gzip stream without recompression, if the archive allows it.
+// gzipContent는 아카이브가 허용하면 재압축 없이 콘텐츠를 gzip 스트림으로 반환합니다.
+func (f *archiveFile) gzipContent() (*io.SectionReader, bool) {
+	if f.entry.gzip == nil {
+		return nil, false
+	}
+	return f.entry.gzip(), true
+}
+
+// archiveInfo implements os.FileInfo for archive entries.
+// archiveInfo는 아카이브 항목을 위한 os.FileInfo를 구현합니다.
+type archiveInfo struct {
+	e *archiveEntry
+}
+
+func (i archiveInfo) Name() string       { return i.e.name }
+func (i archiveInfo) Size() int64        { return i.e.size }
+func (i archiveInfo) Mode() fs.FileMode  { return i.e.mode }
+func (i archiveInfo) ModTime() time.Time { return i.e.modTime }
+func (i archiveInfo) IsDir() bool        { return i.e.mode.IsDir() }
+func (i archiveInfo) Sys() any           { return nil }
@@ -0,0 +1,228 @@
+package fileserver
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy describes the caching headers sent with a served file.
+// The zero value sends no caching headers.
+//
+// CachePolicy는 제공되는 파일과 함께 전송되는 캐시 헤더를 나타냅니다.
+// 제로 값은 캐시 헤더를 전송하지 않습니다.
+type CachePolicy struct {
+	// Public and Private set the "public" and "private" directives.
+	// Public과 Private는 "public"과 "private" 지시문을 설정합니다.
+	Public  bool
+	Private bool
+	// NoCache requires revalidation before every use of a stored response.
+	// NoCache는 저장된 응답을 사용할 때마다 재검증을 요구합니다.
+	NoCache bool
+	// NoStore forbids storing the response. All other directives are dropped.
+	// NoStore는 응답 저장을 금지합니다. 다른 모든 지시문은 무시됩니다.
+	NoStore bool
+	// MustRevalidate forbids serving the response stale once it expired.
+	// MustRevalidate는 만료된 응답을 오래된 상태로 제공하는 것을 금지합니다.
+	MustRevalidate bool
+	// Immutable tells clients the response never changes while fresh.
+	// Immutable은 응답이 신선한 동안 절대 바뀌지 않음을 클라이언트에 알립니다.
+	Immutable bool
+	// MaxAge sets the "max-age" directive.
+	// MaxAge는 "max-age" 지시문을 설정합니다.
+	MaxAge time.Duration
+	// SMaxAge sets the "s-maxage" directive for shared caches.
+	// SMaxAge는 공유 캐시를 위한 "s-maxage" 지시문을 설정합니다.
+	SMaxAge time.Duration
+	// StaleWhileRevalidate sets the "stale-while-revalidate" directive.
+	// StaleWhileRevalidate는 "stale-while-revalidate" 지시문을 설정합니다.
+	StaleWhileRevalidate time.Duration
+	// StaleIfError sets the "stale-if-error" directive.
+	// StaleIfError는 "stale-if-error" 지시문을 설정합니다.
+	StaleIfError time.Duration
+	// Expires additionally sends an Expires header of now plus MaxAge, for HTTP/1.0 caches.
+	// Expires는 HTTP/1.0 캐시를 위해 현재 시각에 MaxAge를 더한 Expires 헤더를 추가로 전송합니다.
+	Expires bool
+	// CDNMaxAge, if positive, sends "CDN-Cache-Control: max-age=<value>" so CDNs can cache longer than browsers.
+	// CDNMaxAge가 양수이면 "CDN-Cache-Control: max-age=<값>"을 전송하여 CDN이 브라우저보다 오래 캐시할 수 있게 합니다.
+	CDNMaxAge time.Duration
+}
+
+// String returns the Cache-Control header value of the policy.
+// String은 정책의 Cache-Control 헤더 값을 반환합니다.
+func (p CachePolicy) String() string {
+	if p.NoStore {
+		return "no-store"
+	}
+	var directives []string
+	add := func(set bool, directive string) {
+		if set {
+			directives = append(directives, directive)
+		}
+	}
+	addSeconds := func(d time.Duration, directive string) {
+		if d > 0 {
+			directives = append(directives, directive+"="+strconv.FormatInt(int64(d/time.Second), 10))
+		}
+	}
+	add(p.Public, "public")
+	add(p.Private, "private")
+	add(p.NoCache, "no-cache")
+	addSeconds(p.MaxAge, "max-age")
+	addSeconds(p.SMaxAge, "s-maxage")
+	add(p.MustRevalidate, "must-revalidate")
+	addSeconds(p.StaleWhileRevalidate, "stale-while-revalidate")
+	addSeconds(p.StaleIfError, "stale-if-error")
+	add(p.Immutable, "immutable")
+	return strings.Join(directives, ", ")
+}
+
+// apply sets the caching headers of the policy.
+// apply는 정책의 캐시 헤더를 설정합니다.
+func (p CachePolicy) apply(h http.Header) {
+	if v := p.String(); v != "" {
+		h.Set("Cache-Control", v)
+	}
+	if p.Expires && !p.NoStore && p.MaxAge > 0 {
+		h.Set("Expires", time.Now().Add(p.MaxAge).UTC().Format(http.TimeFormat))
+	}
+	if p.CDNMaxAge > 0 && !p.NoStore {
+		h.Set("CDN-Cache-Control", "max-age="+strconv.FormatInt(int64(p.CDNMaxAge/time.Second), 10))
+	}
+}
+
+// legacyCachePolicy converts the cacheMaxAgeSeconds parameter of Run into a CachePolicy.
+// legacyCachePolicy는 Run의 cacheMaxAgeSeconds 파라미터를 CachePolicy로 변환합니다.
+func legacyCachePolicy(cacheMaxAgeSeconds int) CachePolicy {
+	if cacheMaxAgeSeconds > 0 {
+		return CachePolicy{Public: true, MaxAge: time.Duration(cacheMaxAgeSeconds) * time.Second}
+	} else if cacheMaxAgeSeconds < 0 {
+		return CachePolicy{NoStore: true}
+	}
+	return CachePolicy{}
+}
+
+// CacheMatcher reports whether a cache rule applies to a file, given its cleaned path and content type.
+// CacheMatcher는 정리된 경로와 콘텐츠 타입을 받아 캐시 규칙이 파일에 적용되는지 여부를 반환합니다.
+type CacheMatcher interface {
+	Matches(name, contentType string) bool
+}
+
+// CacheMatcherFunc adapts a function to CacheMatcher.
+// CacheMatcherFunc는 함수를 CacheMatcher에 맞게 변환합니다.
+type CacheMatcherFunc func(name, contentType string) bool
+
+// Matches calls f(name, contentType).
+// Matches는 f(name, contentType)를 호출합니다.
+func (f CacheMatcherFunc) Matches(name, contentType string) bool {
+	return f(name, contentType)
+}
+
+// CacheRule applies Policy to the files matched by Match.
+// CacheRule은 Match에 일치하는 파일에 Policy를 적용합니다.
+type CacheRule struct {
+	Match  CacheMatcher
+	Policy CachePolicy
+}
+
+// globMatcher matches files against glob patterns, see MatchGlob.
+// globMatcher는 파일을 glob 패턴과 비교합니다. MatchGlob을 참고하세요.
+type globMatcher []string
+
+// Matches reports whether name matches one of the patterns.
+// Matches는 name이 패턴 중 하나와 일치하는지 여부를 반환합니다.
+func (g globMatcher) Matches(name, _ string) bool {
+	return matchGlobs(g, name)
+}
+
+// MatchGlob matches files against glob patterns. A pattern ending in "/" matches everything below that
+// directory ("/assets/"), a pattern containing "/" is matched against the whole path ("/img/*.png"),
+// and any other pattern is matched against the base name ("*.html"). Malformed patterns are reported by New.
+//
+// MatchGlob은 파일을 glob 패턴과 비교합니다. "/"로 끝나는 패턴은 해당 디렉토리 아래 전체에 일치하고("/assets/"),
+// "/"를 포함하는 패턴은 전체 경로와 비교되며("/img/*.png"), 그 외 패턴은 기본 이름과 비교됩니다("*.html").
+// 형식이 잘못된 패턴은 New가 보고합니다.
+func MatchGlob(patterns ...string) CacheMatcher {
+	return globMatcher(append([]string(nil), patterns...))
+}
+
+// MatchExt matches files by extension, e.g. MatchExt(".png", ".jpg").
+// MatchExt는 확장자로 파일을 비교합니다(예: MatchExt(".png", ".jpg")).
+func MatchExt(exts ...string) CacheMatcher {
+	return CacheMatcherFunc(func(name, _ string) bool {
+		ext := path.Ext(name)
+		for _, e := range exts {
+			if strings.EqualFold(ext, e) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// MatchContentType matches files by media type. A type ending in "/*" matches the whole top-level type ("image/*").
+// MatchContentType은 미디어 타입으로 파일을 비교합니다. "/*"로 끝나는 타입은 최상위 타입 전체에 일치합니다("image/*").
+func MatchContentType(types ...string) CacheMatcher {
+	return CacheMatcherFunc(func(_, contentType string) bool {
+		mediaType, _, _ := strings.Cut(contentType, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if mediaType == "" {
+			return false
+		}
+		for _, t := range types {
+			t = strings.ToLower(t)
+			if prefix, ok := strings.CutSuffix(t, "*"); ok {
+				if strings.HasPrefix(mediaType, prefix) {
+					return true
+				}
+			} else if mediaType == t {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// matchGlobs reports whether name matches one of the glob patterns, as described for MatchGlob.
+// matchGlobs는 MatchGlob에 설명된 대로 name이 glob 패턴 중 하나와 일치하는지 여부를 반환합니다.
+func matchGlobs(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether name matches the glob pattern p, as described for MatchGlob.
+// matchGlob은 MatchGlob에 설명된 대로 name이 glob 패턴 p와 일치하는지 여부를 반환합니다.
+func matchGlob(p, name string) bool {
+	switch {
+	case strings.HasSuffix(p, "/"):
+		return strings.HasPrefix(name, p)
+	case strings.Contains(p, "/"):
+		ok, _ := path.Match(p, name)
+		return ok
+	default:
+		ok, _ := path.Match(p, path.Base(name))
+		return ok
+	}
+}
+
+// cachePolicyFor returns the policy of the first rule matching name. It reports false if no rule matches.
+// cachePolicyFor는 name에 일치하는 첫 규칙의 정책을 반환합니다. 일치하는 규칙이 없으면 false를 반환합니다.
+func cachePolicyFor(rules []CacheRule, name string) (CachePolicy, bool) {
+	if len(rules) == 0 {
+		return CachePolicy{}, false
+	}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	for _, rule := range rules {
+		if rule.Match != nil && rule.Match.Matches(name, contentType) {
+			return rule.Policy, true
+		}
+	}
+	return CachePolicy{}, false
+}
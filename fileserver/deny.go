@@ -0,0 +1,66 @@
+package fileserver
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// isDenied reports whether the cleaned path name must not be served. Allow patterns take precedence over
+// the deny patterns, but lift the dotfile rule only for the dot segments they spell out: "/.well-known/"
+// allows "/.well-known/x" but not "/.well-known/.git/x", and "*.txt" allows neither "/.env.txt" nor "/.git/x.txt".
+// A directory pattern also allows the directory itself, so "/.well-known" can be redirected to "/.well-known/".
+// isDenied는 정리된 경로 name을 제공하면 안 되는지 여부를 반환합니다. 허용 패턴은 거부 패턴보다 우선하지만,
+// dotfile 규칙은 패턴에 명시된 점 세그먼트에 대해서만 해제합니다. "/.well-known/"은 "/.well-known/x"는 허용하지만
+// "/.well-known/.git/x"는 허용하지 않으며, "*.txt"는 "/.env.txt"와 "/.git/x.txt"를 모두 허용하지 않습니다.
+// 디렉토리 패턴은 디렉토리 자체도 허용하므로 "/.well-known"을 "/.well-known/"으로 리다이렉트할 수 있습니다.
+func (c *config) isDenied(name string) bool {
+	segments := strings.Split(name, "/")
+	named := make([]bool, len(segments))
+	allowed := false
+	for _, p := range c.allowPatterns {
+		if matchGlob(p, name) || strings.HasSuffix(p, "/") && name == strings.TrimSuffix(p, "/") {
+			allowed = true
+			markDotSegments(p, named)
+		}
+	}
+	if !c.dotfiles {
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, ".") && !named[i] {
+				return true
+			}
+		}
+	}
+	return !allowed && matchGlobs(c.denyPatterns, name)
+}
+
+// markDotSegments marks the segments of a name matched by the glob pattern p that p spells out with a leading ".".
+// Basename patterns can only name the last segment.
+// markDotSegments는 glob 패턴 p와 일치한 이름의 세그먼트 중 p가 "."으로 시작하도록 명시한 세그먼트를 표시합니다.
+// 기본 이름 패턴은 마지막 세그먼트만 명시할 수 있습니다.
+func markDotSegments(p string, named []bool) {
+	if !strings.Contains(p, "/") {
+		if strings.HasPrefix(p, ".") {
+			named[len(named)-1] = true
+		}
+		return
+	}
+	// Path patterns match segment by segment, since wildcards never match "/"
+	// 와일드카드는 "/"와 일치하지 않으므로 경로 패턴은 세그먼트 단위로 일치함
+	for i, segment := range strings.Split(strings.TrimSuffix(p, "/"), "/") {
+		if i < len(named) && strings.HasPrefix(segment, ".") {
+			named[i] = true
+		}
+	}
+}
+
+// validateGlobs checks that every pattern is a well-formed glob pattern.
+// validateGlobs는 모든 패턴이 올바른 형식의 glob 패턴인지 확인합니다.
+func validateGlobs(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("fileserver: invalid glob pattern %q: %v", p, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package fileserver
+
+import (
+	"crypto/sha512"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errIsDirectory is returned when a digest is requested for a directory.
+// errIsDirectory는 디렉토리에 대한 다이제스트를 요청했을 때 반환됩니다.
+var errIsDirectory = errors.New("fileserver: is a directory")
+
+// digestEntry is a cached file digest together with the file state it was computed from.
+// digestEntry는 캐시된 파일 다이제스트와 그것을 계산할 때의 파일 상태입니다.
+type digestEntry struct {
+	ino     uint64
+	modTime time.Time
+	size    int64
+	sum     []byte
+}
+
+// digestCache computes SHA-384 digests of files in an http.FileSystem and caches them until the file's
+// inode, on Unix systems, its modification time or its size changes. The inode catches files replaced
+// by a rename with the same size and modification time, as deployment tools commonly do.
+// digestCache는 http.FileSystem 내 파일의 SHA-384 다이제스트를 계산하고, 파일의 아이노드(Unix 시스템),
+// 수정 시간 또는 크기가 바뀔 때까지 캐시합니다. 아이노드는 배포 도구가 흔히 하듯 크기와 수정 시간이
+// 같은 파일로 이름을 바꿔 교체한 경우를 감지합니다.
+type digestCache struct {
+	fs      http.FileSystem
+	mu      sync.RWMutex
+	entries map[string]digestEntry
+}
+
+// newDigestCache creates a digestCache for fs.
+// newDigestCache는 fs를 위한 digestCache를 생성합니다.
+func newDigestCache(fs http.FileSystem) *digestCache {
+	return &digestCache{fs: fs, entries: make(map[string]digestEntry)}
+}
+
+// sum returns the SHA-384 digest of the named file, computing it if the cached value is missing or stale.
+// sum은 지정된 파일의 SHA-384 다이제스트를 반환하며, 캐시된 값이 없거나 오래되었으면 다시 계산합니다.
+func (c *digestCache) sum(name string) ([]byte, error) {
+	name = path.Clean("/" + name)
+	f, err := c.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return c.sumFile(name, f, stat)
+}
+
+// sumFile returns the SHA-384 digest of the named file from the already opened f, hashing f if the cached
+// value is missing or stale. f is rewound afterwards, so it can still be served.
+// sumFile은 이미 열린 f로부터 지정된 파일의 SHA-384 다이제스트를 반환하며, 캐시된 값이 없거나 오래되었으면
+// f를 해시합니다. 이후 f는 되감기므로 계속 제공할 수 있습니다.
+func (c *digestCache) sumFile(name string, f io.ReadSeeker, stat os.FileInfo) ([]byte, error) {
+	if stat.IsDir() {
+		return nil, errIsDirectory
+	}
+	ino, _ := fileInode(stat)
+
+	c.mu.RLock()
+	e, ok := c.entries[name]
+	c.mu.RUnlock()
+	if ok && e.ino == ino && e.modTime.Equal(stat.ModTime()) && e.size == stat.Size() {
+		return e.sum, nil
+	}
+
+	h := sha512.New384()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	e = digestEntry{ino: ino, modTime: stat.ModTime(), size: stat.Size(), sum: h.Sum(nil)}
+
+	c.mu.Lock()
+	c.entries[name] = e
+	c.mu.Unlock()
+	return e.sum, nil
+}
+
+// digestBinding hashes through its own digest cache until a file server binds its digest cache,
+// from then on names are resolved and hashed like requests to that file server.
+// digestBinding은 파일 서버가 자신의 다이제스트 캐시를 바인딩하기 전까지 자체 다이제스트 캐시로 해시하며,
+// 이후에는 이름을 해당 파일 서버에 대한 요청처럼 확인하고 해시합니다.
+type digestBinding struct {
+	own   *digestCache
+	bound atomic.Pointer[digestCache]
+}
+
+// cache returns the bound digest cache, or the own one if none is bound.
+// cache는 바인딩된 다이제스트 캐시를 반환하며, 없으면 자체 캐시를 반환합니다.
+func (b *digestBinding) cache() *digestCache {
+	if c := b.bound.Load(); c != nil {
+		return c
+	}
+	return b.own
+}
+
+// bind binds the digest cache of a file server. It reports false if another one is already bound.
+// bind는 파일 서버의 다이제스트 캐시를 바인딩합니다. 이미 다른 캐시가 바인딩되어 있으면 false를 반환합니다.
+func (b *digestBinding) bind(c *digestCache) bool {
+	return b.bound.CompareAndSwap(nil, c) || b.bound.Load() == c
+}
+
+// walk computes the digest of every file below root, calling fn for each one.
+// walk는 root 아래 모든 파일의 다이제스트를 계산하며, 각 파일마다 fn을 호출합니다.
+func (c *digestCache) walk(root string, fn func(name string, sum []byte)) error {
+	root = path.Clean("/" + root)
+	f, err := c.fs.Open(root)
+	if err != nil {
+		return err
+	}
+	infos, err := f.Readdir(-1)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		name := path.Join(root, info.Name())
+		if info.IsDir() {
+			if err := c.walk(name, fn); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			// Symbolic links and special files are hashed on first use
+			// 심볼릭 링크와 특수 파일은 처음 사용될 때 해시됨
+			continue
+		}
+		sum, err := c.sum(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Denied names are hidden by the file server
+			// 거부된 이름은 파일 서버가 숨김
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if fn != nil {
+			fn(name, sum)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package fileserver
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/DevNewbie1826/httperror"
+)
+
+// errorPage is the response configured for a status code: a file in the served file system or a handler.
+// errorPage는 상태 코드에 설정된 응답으로, 제공되는 파일 시스템 내 파일이거나 핸들러입니다.
+type errorPage struct {
+	name    string
+	handler http.Handler
+}
+
+// serveStatus renders the error response for status: the configured error page or handler, otherwise httperror.
+// Headers describing the file that failed are removed first, so the error body is never labeled as e.g. gzip.
+// serveStatus는 status에 대한 오류 응답을 렌더링합니다. 설정된 오류 페이지나 핸들러를 사용하며, 없으면 httperror를 사용합니다.
+// 실패한 파일을 설명하는 헤더를 먼저 제거하므로 오류 본문이 gzip 등으로 잘못 표시되지 않습니다.
+func (h *handler) serveStatus(w http.ResponseWriter, r *http.Request, status int) {
+	// Drop the representation, validators and caching headers of the file that failed
+	// 실패한 파일의 표현, 검증자, 캐시 헤더를 제거
+	header := w.Header()
+	for _, name := range []string{"ETag", "Last-Modified", "Expires", "CDN-Cache-Control", "Content-Encoding", "Content-Type", "Content-Length", "Content-Range", "Accept-Ranges"} {
+		header.Del(name)
+	}
+	header.Set("Cache-Control", "no-store")
+
+	if page, ok := h.cfg.errorPages[status]; ok {
+		if page.handler != nil {
+			sw := &statusWriter{ResponseWriter: w, status: status}
+			page.handler.ServeHTTP(sw, r)
+			// A handler that wrote nothing still answers with the status
+			// 아무것도 쓰지 않은 핸들러도 해당 상태로 응답
+			sw.WriteHeader(status)
+			return
+		}
+		if h.serveErrorFile(w, r, page.name, status) {
+			return
+		}
+	}
+	httperror.Respond(w, r, httperror.New(status, http.StatusText(status)))
+}
+
+// serveErrorFile writes the named file with status. It reports false if the file cannot be read.
+// serveErrorFile은 지정된 파일을 status와 함께 씁니다. 파일을 읽을 수 없으면 false를 반환합니다.
+func (h *handler) serveErrorFile(w http.ResponseWriter, r *http.Request, name string, status int) bool {
+	f, _, err := h.open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	body, err := io.ReadAll(f)
+	if err != nil {
+		return false
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+	return true
+}
+
+// statusWriter forces the status code and no-store caching of responses written by custom error handlers.
+// statusWriter는 사용자 정의 오류 핸들러가 쓰는 응답의 상태 코드와 no-store 캐싱을 강제합니다.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader writes the configured status code, whatever code the handler passes.
+// WriteHeader는 핸들러가 전달한 코드와 관계없이 설정된 상태 코드를 씁니다.
+func (w *statusWriter) WriteHeader(int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.Header().Set("Cache-Control", "no-store")
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// Write writes the header first if the handler did not.
+// Write는 핸들러가 헤더를 쓰지 않았으면 먼저 헤더를 씁니다.
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(w.status)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+// Unwrap은 http.ResponseController를 위해 내부 http.ResponseWriter를 반환합니다.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
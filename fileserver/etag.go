@@ -0,0 +1,72 @@
+package fileserver
+
+import (
+	"encoding/hex"
+	"io"
+	"os"
+	"strconv"
+)
+
+// etagLength is the number of hex characters of the content hash used as ETag.
+// etagLength는 ETag로 사용되는 콘텐츠 해시의 16진수 문자 수입니다.
+const etagLength = 32
+
+// ETagStrategy selects how the ETag of a served file is generated. All strategies produce strong ETags,
+// so http.ServeContent honors If-None-Match, If-Match and If-Range with them.
+// ETagStrategy는 제공되는 파일의 ETag 생성 방식을 선택합니다. 모든 전략은 강한 ETag를 생성하므로
+// http.ServeContent가 If-None-Match, If-Match, If-Range를 이에 맞게 처리합니다.
+type ETagStrategy int
+
+const (
+	// ETagNone sends no ETag; conditional requests rely on Last-Modified only. This is the default of New.
+	// ETagNone은 ETag를 전송하지 않으며, 조건부 요청은 Last-Modified에만 의존합니다. New의 기본값입니다.
+	ETagNone ETagStrategy = iota
+	// ETagContentHash derives the ETag from a SHA-384 hash of the content, so it survives deployments that
+	// reset modification times. Hashes are cached in memory until the file's inode, on Unix systems, its
+	// modification time or its size changes. This is the default of NewFS.
+	// ETagContentHash는 콘텐츠의 SHA-384 해시에서 ETag를 얻으므로 수정 시간을 초기화하는 배포 후에도 유지됩니다.
+	// 해시는 파일의 아이노드(Unix 시스템), 수정 시간 또는 크기가 바뀔 때까지 메모리에 캐시됩니다. NewFS의 기본값입니다.
+	ETagContentHash
+	// ETagSizeModTime derives the ETag from the size and modification time, without reading the file.
+	// ETagSizeModTime은 파일을 읽지 않고 크기와 수정 시간에서 ETag를 얻습니다.
+	ETagSizeModTime
+	// ETagInode additionally includes the inode number on Unix systems, like Apache's FileETag INode MTime Size.
+	// Elsewhere it behaves like ETagSizeModTime.
+	// ETagInode는 Apache의 FileETag INode MTime Size처럼 Unix 시스템에서 아이노드 번호를 추가로 포함합니다.
+	// 그 외 환경에서는 ETagSizeModTime과 같이 동작합니다.
+	ETagInode
+)
+
+// etag returns the ETag of the named file f according to the configured strategy, or "" if ETags are disabled.
+// etag는 설정된 전략에 따라 지정된 파일 f의 ETag를 반환하며, ETag가 비활성화되면 ""를 반환합니다.
+func (h *handler) etag(name string, f io.ReadSeeker, stat os.FileInfo) (string, error) {
+	switch h.cfg.etag {
+	case ETagContentHash:
+		sum, err := h.digests.sumFile(name, f, stat)
+		if err != nil {
+			return "", err
+		}
+		return contentHashETag(sum), nil
+	case ETagSizeModTime:
+		return `"` + sizeModTime(stat) + `"`, nil
+	case ETagInode:
+		if ino, ok := fileInode(stat); ok {
+			return `"` + strconv.FormatUint(ino, 16) + "-" + sizeModTime(stat) + `"`, nil
+		}
+		return `"` + sizeModTime(stat) + `"`, nil
+	default:
+		return "", nil
+	}
+}
+
+// contentHashETag formats a SHA-384 content hash as ETag.
+// contentHashETag는 SHA-384 콘텐츠 해시를 ETag 형식으로 변환합니다.
+func contentHashETag(sum []byte) string {
+	return `"` + hex.EncodeToString(sum)[:etagLength] + `"`
+}
+
+// sizeModTime formats the modification time and size of a file in hex, e.g. "18c5f2a1b3c4d5e6-1a2b".
+// sizeModTime은 파일의 수정 시간과 크기를 16진수로 표현합니다(예: "18c5f2a1b3c4d5e6-1a2b").
+func sizeModTime(stat os.FileInfo) string {
+	return strconv.FormatInt(stat.ModTime().UnixNano(), 16) + "-" + strconv.FormatInt(stat.Size(), 16)
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package fileserver
+
+import "os"
+
+// fileInode reports false, since inode numbers are only available on Unix systems.
+// fileInode는 아이노드 번호가 Unix 시스템에서만 제공되므로 false를 반환합니다.
+func fileInode(os.FileInfo) (uint64, bool) {
+	return 0, false
+}
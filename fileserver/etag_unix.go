@@ -0,0 +1,17 @@
+//go:build unix
+
+package fileserver
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileInode returns the inode number of a file, if the file info carries one.
+// fileInode는 파일 정보에 아이노드 번호가 있으면 이를 반환합니다.
+func fileInode(stat os.FileInfo) (uint64, bool) {
+	if st, ok := stat.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino), true
+	}
+	return 0, false
+}
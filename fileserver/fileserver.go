@@ -1,52 +1,22 @@
 package fileserver
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/DevNewbie1826/httperror"
 	"github.com/go-chi/chi/v5"
 )
 
 // --- Custom FileSystem wrappers ---
 
-// noListFileSystem wraps an http.FileSystem to prevent directory listing.
-// If a directory is requested, it attempts to serve 'index.html' from that directory.
-// If 'index.html' is not found, it returns a 'not found' error.
-//
-// noListFileSystem은 디렉토리 리스팅을 방지하기 위해 http.FileSystem을 래핑합니다.
-// 디렉토리가 요청되면 해당 디렉토리의 'index.html'을 서빙하려고 시도합니다.
-// 'index.html'을 찾지 못하면 'not found' 오류를 반환합니다.
-type noListFileSystem struct {
-	// fs is the underlying file system.
-	// fs는 내부 파일 시스템입니다.
-	fs http.FileSystem
-}
-
-// Open opens the named file. It overrides the default behavior for directories.
-//
-// Open은 지정된 이름의 파일을 엽니다. 디렉토리에 대한 기본 동작을 재정의합니다.
-func (nlfs noListFileSystem) Open(name string) (http.File, error) {
-	f, err := nlfs.fs.Open(name)
-	if err != nil {
-		return nil, err
-	}
-	s, err := f.Stat()
-	if err != nil {
-		f.Close()
-		return nil, err
-	}
-	if s.IsDir() {
-		f.Close()
-		return nil, os.ErrPermission
-	}
-	return f, nil
-}
-
 // prefixAddingFileSystem wraps an http.FileSystem to prepend a path prefix to every request.
 // This is used to maintain backward compatibility with the old 'stripPrefix' parameter,
 // which behaved as an "add-prefix".
@@ -70,13 +40,463 @@ func (pfs prefixAddingFileSystem) Open(name string) (http.File, error) {
 	return pfs.fs.Open(prefixedName)
 }
 
+// fileSystemFunc adapts a function to http.FileSystem.
+// fileSystemFunc는 함수를 http.FileSystem에 맞게 변환합니다.
+type fileSystemFunc func(name string) (http.File, error)
+
+// Open calls f(name).
+// Open은 f(name)을 호출합니다.
+func (f fileSystemFunc) Open(name string) (http.File, error) {
+	return f(name)
+}
+
+// source is a file system files are served from, with the prefix applied.
+// source는 파일을 제공하는 파일 시스템이며, 접두사가 적용되어 있습니다.
+type source struct {
+	// fs is the file system used by the generic path, with the prefix applied.
+	// fs는 범용 경로에서 사용하는 파일 시스템으로, 접두사가 적용되어 있습니다.
+	fs http.FileSystem
+	// dir is the local directory used by the http.Dir fast path, or "" if the fast path is not available.
+	// dir은 http.Dir 빠른 경로에서 사용하는 로컬 디렉토리이며, 빠른 경로를 사용할 수 없으면 ""입니다.
+	dir string
+	// confined opens dir through os.Root, which keeps the http.Dir fast path inside dir,
+	// unless symbolic links are followed everywhere.
+	// confined는 심볼릭 링크를 모든 곳에서 따라가지 않는 한 dir을 os.Root로 열어
+	// http.Dir 빠른 경로를 dir 안으로 제한합니다.
+	confined bool
+	// links resolves symbolic links according to the symlink policy, or is nil if the file system does not report them.
+	// links는 심볼릭 링크 정책에 따라 심볼릭 링크를 해석하며, 파일 시스템이 링크를 보고하지 않으면 nil입니다.
+	links linkFileSystem
+	// denied reports whether a path must not be served; it is checked again on link-resolved paths.
+	// denied는 경로를 제공하면 안 되는지 여부를 반환하며, 링크가 해석된 경로에 대해 다시 확인됩니다.
+	denied func(name string) bool
+}
+
+// newSource prepares fs for serving with the configured prefix and symlink policy.
+// newSource는 설정된 접두사와 심볼릭 링크 정책으로 fs를 제공할 준비를 합니다.
+func newSource(fs http.FileSystem, cfg *config) source {
+	src := source{fs: fs, denied: cfg.isDenied}
+	if cfg.prefix != "" {
+		src.fs = prefixAddingFileSystem{prefix: cfg.prefix, fs: fs}
+	}
+	if d, ok := fs.(http.Dir); ok {
+		dir := string(d)
+		if dir == "" {
+			dir = "."
+		}
+		src.dir = filepath.Join(dir, filepath.FromSlash(path.Clean("/"+cfg.prefix)))
+		src.confined = cfg.symlinks != SymlinksFollow
+		src.links = dirLinks{dir: src.dir}
+	} else if links, ok := fs.(linkFileSystem); ok {
+		src.links = prefixLinks{prefix: path.Clean("/" + cfg.prefix), fs: links}
+	}
+	return src
+}
+
+// open opens the named file or directory, enforcing the symlink policy and denying link targets that are denied by name.
+// open은 심볼릭 링크 정책을 적용하고 이름으로 거부되는 링크 대상을 거부하며 지정된 파일 또는 디렉토리를 엽니다.
+func (s source) open(name string, policy SymlinkPolicy) (http.File, error) {
+	if s.confined {
+		return s.openRoot(name, policy)
+	}
+	if s.links != nil {
+		resolved, err := resolveLinks(s.links, name, policy)
+		switch {
+		case err == nil:
+			if resolved != name && s.denied(resolved) {
+				return nil, os.ErrNotExist
+			}
+			name = resolved
+		case policy != SymlinksFollow:
+			return nil, err
+		}
+		// Links leaving the root are followed as they are under SymlinksFollow
+		// SymlinksFollow에서는 루트를 벗어나는 링크를 그대로 따라감
+	}
+	if s.dir != "" {
+		// Optimization: Open local files directly so http.ServeContent can leverage sendfile.
+		// 최적화: 로컬 파일을 직접 열어 http.ServeContent가 sendfile을 활용하도록 함.
+		return os.Open(filepath.Join(s.dir, filepath.FromSlash(name)))
+	}
+	return s.fs.Open(name)
+}
+
+// openRoot opens the named file through an os.Root, which guarantees the file stays inside dir.
+// The root is opened per request, so a directory replaced by a deploy, e.g. by swapping a "current"
+// symbolic link, is served at once and a missing directory is answered with 404.
+//
+// openRoot는 파일이 dir 안에 있음을 보장하는 os.Root를 통해 지정된 파일을 엽니다. 루트는 요청마다 열리므로
+// 배포로 교체된 디렉토리(예: "current" 심볼릭 링크 교체)가 즉시 제공되며, 없는 디렉토리는 404로 응답합니다.
+func (s source) openRoot(name string, policy SymlinkPolicy) (http.File, error) {
+	root, err := os.OpenRoot(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+	resolved, err := resolveLinks(rootLinks{root: root}, name, policy)
+	if err != nil {
+		return nil, err
+	}
+	if resolved != name && s.denied(resolved) {
+		// A link must not reach a path that is denied by name, e.g. "cfg" pointing to ".secrets"
+		// 링크가 이름으로 거부되는 경로에 도달해서는 안 됨(예: ".secrets"를 가리키는 "cfg")
+		return nil, os.ErrNotExist
+	}
+	// Optimization: The *os.File still lets http.ServeContent leverage sendfile
+	// 최적화: *os.File이므로 http.ServeContent가 여전히 sendfile을 활용할 수 있음
+	return root.Open(rootName(resolved))
+}
+
+// --- Handler ---
+
+// handler serves files from an http.FileSystem with the configured options.
+// handler는 설정된 옵션으로 http.FileSystem의 파일을 제공합니다.
+type handler struct {
+	// cfg holds the applied options.
+	// cfg는 적용된 옵션을 보관합니다.
+	cfg config
+	// sources are the file systems files are looked up in, in order; there is more than one for Overlay.
+	// sources는 파일을 순서대로 찾는 파일 시스템이며, Overlay의 경우 둘 이상입니다.
+	sources []source
+	// digests hashes file contents for content-based ETags, fingerprints and integrity, or is nil if none is used.
+	// digests는 콘텐츠 기반 ETag, 지문, 무결성을 위해 파일 콘텐츠를 해시하며, 모두 사용하지 않으면 nil입니다.
+	digests *digestCache
+	// throttle limits the bandwidth and concurrency of large transfers, or is nil if they are not limited.
+	// throttle은 대용량 전송의 대역폭과 동시성을 제한하며, 제한하지 않으면 nil입니다.
+	throttle *throttler
+}
+
+// New creates an http.Handler that serves static files from fs. The handler is router-agnostic:
+// it serves the request path relative to the root of fs, so mount it with http.StripPrefix when
+// serving below a URL prefix. Directory requests serve the directory's index file (see WithIndexFiles),
+// directory listing is prevented unless enabled with WithListing, and errors are rendered through httperror
+// unless an error page is configured (see WithErrorPage).
+//
+// New는 fs의 정적 파일을 제공하는 http.Handler를 생성합니다. 핸들러는 라우터에 의존하지 않으며,
+// 요청 경로를 fs의 루트 기준으로 제공하므로 URL 접두사 아래에서 제공할 때는 http.StripPrefix와 함께 마운트하세요.
+// 디렉토리 요청은 해당 디렉토리의 인덱스 파일을 제공하며(WithIndexFiles 참고), 디렉토리 리스팅은 WithListing으로 활성화하지 않는 한 방지되며
+// 오류는 오류 페이지가 설정되지 않은 한(WithErrorPage 참고) httperror를 통해 렌더링됩니다.
+//
+// Example:
+//
+//	h, err := fileserver.New(http.Dir("./public"), fileserver.WithCachePolicy(fileserver.CachePolicy{Public: true, MaxAge: time.Hour}))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	mux.Handle("/static/", http.StripPrefix("/static", h))
+func New(fs http.FileSystem, opts ...Option) (http.Handler, error) {
+	if fs == nil {
+		return nil, errors.New("fileserver: nil file system")
+	}
+	cfg := config{indexFiles: []string{"index.html"}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	// --- Filesystem Setup ---
+	roots := []http.FileSystem{fs}
+	if o, ok := fs.(overlayFileSystem); ok {
+		roots = o
+	}
+	h := &handler{cfg: cfg}
+	for _, root := range roots {
+		h.sources = append(h.sources, newSource(root, &h.cfg))
+	}
+	if cfg.etag == ETagContentHash || cfg.fingerprints != nil || cfg.integrity != nil {
+		h.digests = newDigestCache(fileSystemFunc(h.openServed))
+	}
+	if cfg.fingerprints != nil && !cfg.fingerprints.digests.bind(h.digests) {
+		return nil, errors.New("fileserver: fingerprinter is already used by another file server")
+	}
+	if cfg.integrity != nil && !cfg.integrity.digests.bind(h.digests) {
+		return nil, errors.New("fileserver: integrity is already used by another file server")
+	}
+	if cfg.throttle != nil {
+		h.throttle = newThrottler(*cfg.throttle)
+	}
+	return h, nil
+}
+
+// ServeHTTP serves the file named by the request path.
+// ServeHTTP는 요청 경로가 가리키는 파일을 제공합니다.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + r.URL.Path)
+
+	// Deny hidden and blocked paths before touching the file system
+	// 파일 시스템에 접근하기 전에 숨김 경로와 차단된 경로를 거부
+	if h.cfg.isDenied(name) {
+		h.serveStatus(w, r, http.StatusNotFound)
+		return
+	}
+	if h.cfg.signer != nil && h.cfg.signer.Verify(r) != nil {
+		h.serveStatus(w, r, http.StatusForbidden)
+		return
+	}
+	policy := h.cachePolicy(name)
+
+	// Resolve fingerprinted names to the logical file
+	// 지문이 포함된 이름을 논리적 파일로 변환
+	if h.cfg.fingerprints != nil {
+		if logical, ok := h.cfg.fingerprints.resolve(name); ok && !h.cfg.isDenied(logical) {
+			name = logical
+			policy = immutableCachePolicy
+		}
+	}
+
+	h.serveFile(w, r, name, policy, true)
+}
+
+// cachePolicy returns the cache policy of the named file: the first matching cache rule, otherwise
+// "no-cache" when fingerprinting is enabled, otherwise the default policy.
+// cachePolicy는 지정된 파일의 캐시 정책을 반환합니다. 처음 일치하는 캐시 규칙, 그렇지 않으면 지문 사용 시
+// "no-cache", 그 외에는 기본 정책입니다.
+func (h *handler) cachePolicy(name string) CachePolicy {
+	if policy, ok := cachePolicyFor(h.cfg.cacheRules, name); ok {
+		return policy
+	}
+	if h.cfg.fingerprints != nil {
+		return revalidateCachePolicy
+	}
+	return h.cfg.cachePolicy
+}
+
+// serveFile serves the named file with the given cache policy. If the file does not exist and
+// fallback is set, the SPA entry file is served instead when the request qualifies for it.
+// serveFile은 주어진 캐시 정책으로 지정된 파일을 제공합니다. 파일이 존재하지 않고 fallback이 설정되어 있으면,
+// 요청이 조건을 만족할 때 SPA 진입 파일을 대신 제공합니다.
+func (h *handler) serveFile(w http.ResponseWriter, r *http.Request, name string, policy CachePolicy, fallback bool) {
+	if c := h.cfg.memoryCache; c != nil {
+		if e := c.get(name, h.stat); e != nil {
+			h.serveEntry(w, r, e, policy)
+			return
+		}
+	}
+
+	f, stat, err := h.open(name)
+	if errors.Is(err, errIsDirectory) {
+		h.serveDirectory(w, r, name)
+		return
+	}
+	if err != nil {
+		if fallback && os.IsNotExist(err) && h.cfg.spaEntry != "" && isSPARoute(r, name) {
+			h.serveFile(w, r, h.cfg.spaEntry, spaCachePolicy, false)
+			return
+		}
+		h.serveError(w, r, err)
+		return
+	}
+	defer f.Close()
+	h.serveOpened(w, r, name, f, stat, policy)
+}
+
+// serveDirectory serves the first existing index file of the named directory. Requests without a trailing
+// slash are redirected to the slash-terminated path first, so relative links in the index file resolve correctly.
+// Directories without an index file are listed if listing is enabled and answered with 403 Forbidden otherwise.
+// serveDirectory는 지정된 디렉토리에서 처음 존재하는 인덱스 파일을 제공합니다. 끝에 슬래시가 없는 요청은 인덱스 파일의
+// 상대 링크가 올바르게 해석되도록 먼저 슬래시로 끝나는 경로로 리다이렉트됩니다.
+// 인덱스 파일이 없는 디렉토리는 리스팅이 활성화되어 있으면 나열되고, 그렇지 않으면 403 Forbidden으로 응답합니다.
+func (h *handler) serveDirectory(w http.ResponseWriter, r *http.Request, name string) {
+	if p := r.URL.Path; p != "" && !strings.HasSuffix(p, "/") {
+		localRedirect(w, r, path.Base(p)+"/")
+		return
+	}
+
+	for _, index := range h.cfg.indexFiles {
+		indexName := path.Join(name, index)
+		if h.cfg.isDenied(indexName) {
+			continue
+		}
+		if c := h.cfg.memoryCache; c != nil {
+			if e := c.get(indexName, h.stat); e != nil {
+				h.serveEntry(w, r, e, h.cachePolicy(indexName))
+				return
+			}
+		}
+		f, stat, err := h.open(indexName)
+		if err != nil {
+			if os.IsNotExist(err) || errors.Is(err, errIsDirectory) {
+				continue
+			}
+			h.serveError(w, r, err)
+			return
+		}
+		defer f.Close()
+		h.serveOpened(w, r, indexName, f, stat, h.cachePolicy(indexName))
+		return
+	}
+	if h.cfg.listing != nil {
+		h.serveListing(w, r, name)
+		return
+	}
+	h.serveError(w, r, os.ErrPermission)
+}
+
+// serveOpened serves the opened file, from the memory cache if one is configured and the file fits.
+// serveOpened는 열린 파일을 제공하며, 메모리 캐시가 설정되어 있고 파일이 들어가면 캐시를 통해 제공합니다.
+func (h *handler) serveOpened(w http.ResponseWriter, r *http.Request, name string, f http.File, stat os.FileInfo, policy CachePolicy) {
+	if c := h.cfg.memoryCache; c != nil {
+		e, err := h.cacheFile(c, name, f, stat)
+		if err != nil {
+			h.serveError(w, r, err)
+			return
+		}
+		if e != nil {
+			h.serveEntry(w, r, e, policy)
+			return
+		}
+	}
+	h.serveContent(w, r, name, f, stat, policy)
+}
+
+// serveContent writes the headers of the configured policies and serves the content of the named file f.
+// serveContent는 설정된 정책의 헤더를 쓰고 지정된 파일 f의 내용을 제공합니다.
+func (h *handler) serveContent(w http.ResponseWriter, r *http.Request, name string, f http.File, stat os.FileInfo, policy CachePolicy) {
+	// Apply caching policy
+	// 캐시 정책 적용
+	policy.apply(w.Header())
+	h.cfg.applyHeaders(w.Header())
+
+	// Validators for conditional requests, evaluated by http.ServeContent
+	// http.ServeContent가 평가하는 조건부 요청용 검증자
+	etag, err := h.etag(name, f, stat)
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	modTime := stat.ModTime()
+	if !h.cfg.modTime.IsZero() {
+		modTime = h.cfg.modTime
+	}
+
+	// Send deflated archive entries as they are stored to clients accepting gzip
+	// gzip을 허용하는 클라이언트에게 deflate된 아카이브 항목을 저장된 그대로 전송
+	if gz, ok := f.(gzipFile); ok {
+		if content, ok := gz.gzipContent(); ok {
+			if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+				w.Header().Add("Vary", "Accept-Encoding")
+				if acceptsEncoding(r, "gzip") {
+					w.Header().Set("Content-Type", contentType)
+					w.Header().Set("Content-Encoding", "gzip")
+					if etag != "" {
+						w.Header().Set("ETag", strings.TrimSuffix(etag, `"`)+`-gzip"`)
+					}
+					h.serveBody(w, r, name, stat.Name(), modTime, content, content.Size())
+					return
+				}
+			}
+		}
+	}
+
+	h.serveBody(w, r, name, stat.Name(), modTime, f, stat.Size())
+}
+
+// serveBody serves content of the given size with http.ServeContent, applying the range limits and throttling
+// configured for name. Throttling is sized by the bytes actually sent. base is the file name http.ServeContent
+// derives the content type from.
+//
+// serveBody는 name에 설정된 범위 제한과 전송 제한을 적용하여 주어진 크기의 content를 http.ServeContent로 제공합니다.
+// 전송 제한은 실제로 보내는 바이트 수를 기준으로 합니다. base는 http.ServeContent가 콘텐츠 타입을 유추하는 파일 이름입니다.
+func (h *handler) serveBody(w http.ResponseWriter, r *http.Request, name, base string, modTime time.Time, content io.ReadSeeker, size int64) {
+	if h.throttle != nil {
+		rw := w
+		gate := h.throttle.wrap(w, r, size, func() {
+			// Large transfers end at unpredictable times; ask the client to retry shortly
+			// 대용량 전송은 언제 끝날지 예측할 수 없으므로 클라이언트에 곧 재시도하도록 요청
+			rw.Header().Set("Retry-After", "1")
+			h.serveStatus(rw, r, http.StatusServiceUnavailable)
+		})
+		defer gate.finish()
+		w = gate
+	}
+	w, r = h.limitRanges(w, r, name, size)
+	http.ServeContent(w, r, base, modTime, content)
+}
+
+// open opens the named file and returns it with its file info. Directories are rejected with errIsDirectory.
+// open은 지정된 파일을 열어 파일 정보와 함께 반환합니다. 디렉토리는 errIsDirectory로 거부됩니다.
+func (h *handler) open(name string) (http.File, os.FileInfo, error) {
+	f, err := h.openFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	// Prevent directory listing
+	// 디렉토리 리스팅 방지
+	if stat.IsDir() {
+		f.Close()
+		return nil, nil, errIsDirectory
+	}
+	return f, stat, nil
+}
+
+// openFile opens the named file or directory, enforcing the symlink policy. With several sources the first
+// source containing name wins, and directories are merged across sources.
+// openFile은 심볼릭 링크 정책을 적용하여 지정된 파일 또는 디렉토리를 엽니다. 소스가 여러 개이면 name을 포함하는
+// 첫 소스가 우선하며, 디렉토리는 소스 간에 병합됩니다.
+func (h *handler) openFile(name string) (http.File, error) {
+	if len(h.sources) == 1 {
+		return h.sources[0].open(name, h.cfg.symlinks)
+	}
+	layers := make([]func(string) (http.File, error), len(h.sources))
+	for i, src := range h.sources {
+		layers[i] = func(name string) (http.File, error) { return src.open(name, h.cfg.symlinks) }
+	}
+	return openOverlay(name, layers)
+}
+
+// openServed opens the named file like a request for it, hiding denied names.
+// openServed는 지정된 파일을 요청처럼 열며, 거부된 이름은 숨깁니다.
+func (h *handler) openServed(name string) (http.File, error) {
+	if h.cfg.isDenied(path.Clean("/" + name)) {
+		return nil, os.ErrNotExist
+	}
+	return h.openFile(name)
+}
+
+// serveError renders the error response matching a file system error, see serveStatus.
+// serveError는 파일 시스템 오류에 맞는 오류 응답을 렌더링합니다. serveStatus를 참고하세요.
+func (h *handler) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case os.IsPermission(err):
+		h.serveStatus(w, r, http.StatusForbidden)
+	case os.IsNotExist(err):
+		h.serveStatus(w, r, http.StatusNotFound)
+	default:
+		h.serveStatus(w, r, http.StatusInternalServerError)
+	}
+}
+
+// localRedirect redirects to a path relative to the request, keeping the query string.
+// localRedirect는 쿼리 문자열을 유지한 채 요청 기준의 상대 경로로 리다이렉트합니다.
+func localRedirect(w http.ResponseWriter, r *http.Request, target string) {
+	if q := r.URL.RawQuery; q != "" {
+		target += "?" + q
+	}
+	w.Header().Set("Location", target)
+	w.WriteHeader(http.StatusMovedPermanently)
+}
+
 // --- Main Function ---
 
 // Run sets up a handler on the given Chi router to serve static files.
 // It allows for configurable caching and uses a custom 404 error handler.
+// It is a convenience wrapper around New and panics if the configuration is invalid.
 //
 // Run은 정적 파일을 제공하기 위해 주어진 Chi 라우터에 핸들러를 설정합니다.
 // 캐싱을 설정할 수 있으며 사용자 정의 404 오류 핸들러를 사용합니다.
+// New를 감싸는 편의 함수이며, 설정이 유효하지 않으면 panic을 발생시킵니다.
 //
 // Parameters:
 //   - r: The Chi router.
@@ -95,97 +515,34 @@ func (pfs prefixAddingFileSystem) Open(name string) (http.File, error) {
 //   - > 0: "Cache-Control: public, max-age=<값>"을 설정합니다.
 //   - < 0: "Cache-Control: no-store"를 설정합니다.
 //   - == 0: 캐싱이 비활성화됩니다 (헤더가 설정되지 않음).
-func Run(r *chi.Mux, urlPath string, fs http.FileSystem, stripPrefix string, cacheMaxAgeSeconds int) {
+//   - opts: Optional settings such as WithCrossOriginResourcePolicy. WithPrefix and WithCachePolicy
+//     take precedence over stripPrefix and cacheMaxAgeSeconds.
+//   - opts: WithCrossOriginResourcePolicy와 같은 선택적 설정입니다. WithPrefix와 WithCachePolicy가
+//     stripPrefix와 cacheMaxAgeSeconds보다 우선합니다.
+func Run(r *chi.Mux, urlPath string, fs http.FileSystem, stripPrefix string, cacheMaxAgeSeconds int, opts ...Option) {
 	// --- Input Validation ---
 	if strings.ContainsAny(urlPath, "{}*") {
 		panic(fmt.Sprintf("FileServer does not permit URL parameters in urlPath: %s", urlPath))
 	}
 
-	// --- Filesystem Setup ---
-	var effectiveFs http.FileSystem = fs
-	if stripPrefix != "" {
-		effectiveFs = prefixAddingFileSystem{prefix: stripPrefix, fs: fs}
+	// --- Handler Setup ---
+	opts = append([]Option{WithPrefix(stripPrefix), WithCachePolicy(legacyCachePolicy(cacheMaxAgeSeconds))}, opts...)
+	h, err := New(fs, opts...)
+	if err != nil {
+		panic(err.Error())
 	}
-	finalFs := noListFileSystem{fs: effectiveFs}
+	mount(r, urlPath, h)
+}
 
-	// --- Handler Setup ---
+// mount registers h on the Chi router below urlPath, redirecting urlPath without a trailing slash.
+// mount는 Chi 라우터의 urlPath 아래에 h를 등록하며, 끝에 슬래시가 없는 urlPath는 리다이렉트합니다.
+func mount(r *chi.Mux, urlPath string, h http.Handler) {
+	// --- Route Registration ---
 	if urlPath != "/" && urlPath[len(urlPath)-1] != '/' {
 		r.Get(urlPath, http.RedirectHandler(urlPath+"/", http.StatusMovedPermanently).ServeHTTP)
 		urlPath += "/"
 	}
 
-	fileServer := http.FileServer(finalFs)
-
-	handlerWithCustom404 := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Optimization: Use http.ServeFile for local directories to leverage sendfile
-		// 최적화: 로컬 디렉토리인 경우 http.ServeFile을 사용하여 sendfile을 활용
-		if d, ok := fs.(http.Dir); ok {
-			basePath := string(d)
-			upath := r.URL.Path
-			if !strings.HasPrefix(upath, "/") {
-				upath = "/" + upath
-			}
-			upath = path.Clean(upath)
-
-			// Construct full path considering stripPrefix
-			// stripPrefix를 고려하여 전체 경로 구성
-			fullPath := filepath.Join(basePath, stripPrefix, upath)
-
-			// Check file existence and type
-			// 파일 존재 여부 및 타입 확인
-			stat, err := os.Stat(fullPath)
-			if err != nil {
-				if os.IsNotExist(err) {
-					httperror.NotFound(w, r)
-				} else {
-					httperror.InternalServerError(w, r)
-				}
-				return
-			}
-
-			// Prevent directory listing
-			// 디렉토리 리스팅 방지
-			if stat.IsDir() {
-				httperror.Forbidden(w, r)
-				return
-			}
-
-			// Apply caching policy
-			// 캐시 정책 적용
-			if cacheMaxAgeSeconds > 0 {
-				w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", cacheMaxAgeSeconds))
-			} else if cacheMaxAgeSeconds < 0 {
-				w.Header().Set("Cache-Control", "no-store")
-			}
-
-			http.ServeFile(w, r, fullPath)
-			return
-		}
-
-		f, err := finalFs.Open(r.URL.Path)
-		if err != nil {
-			if os.IsPermission(err) {
-				httperror.Forbidden(w, r)
-			} else if os.IsNotExist(err) {
-				httperror.NotFound(w, r)
-			} else {
-				httperror.InternalServerError(w, r)
-			}
-			return
-		}
-		f.Close()
-
-		// Apply caching policy
-		// 캐시 정책 적용
-		if cacheMaxAgeSeconds > 0 {
-			w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", cacheMaxAgeSeconds))
-		} else if cacheMaxAgeSeconds < 0 {
-			w.Header().Set("Cache-Control", "no-store")
-		}
-
-		fileServer.ServeHTTP(w, r)
-	})
-
-	fsHandler := http.StripPrefix(urlPath, handlerWithCustom404)
+	fsHandler := http.StripPrefix(urlPath, h)
 	r.Get(urlPath+"*", fsHandler.ServeHTTP)
 }
@@ -0,0 +1,1281 @@
+package fileserver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DevNewbie1826/webUtil/secure"
+	"github.com/go-chi/chi/v5"
+)
+
+// writeFiles creates the given files below a temporary directory and returns the directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// serve sends a GET request for target to h and returns the recorded response.
+func serve(h http.Handler, target string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// fileSystems returns the http.Dir fast path and a generic http.FileSystem over the same directory.
+func fileSystems(dir string) map[string]http.FileSystem {
+	return map[string]http.FileSystem{
+		"dir":     http.Dir(dir),
+		"generic": struct{ http.FileSystem }{http.Dir(dir)},
+	}
+}
+
+// TestNew tests serving files through New on both the http.Dir fast path and the generic path.
+func TestNew(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app.js":        "console.log(1)",
+		"sub/style.css": "body{}",
+	})
+
+	for name, fs := range fileSystems(dir) {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(fs, WithCachePolicy(CachePolicy{Public: true, MaxAge: time.Hour}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := serve(h, "/app.js")
+			if rec.Code != http.StatusOK || rec.Body.String() != "console.log(1)" {
+				t.Errorf("app.js: got %d %q", rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "public, max-age=3600" {
+				t.Errorf("Cache-Control = %q", got)
+			}
+			if rec := serve(h, "/missing.js"); rec.Code != http.StatusNotFound {
+				t.Errorf("missing.js: got %d, want 404", rec.Code)
+			}
+			if rec := serve(h, "/sub/"); rec.Code != http.StatusForbidden {
+				t.Errorf("sub/: got %d, want 403", rec.Code)
+			}
+		})
+	}
+}
+
+// TestIndexFiles tests index file serving and trailing-slash redirects for directories.
+func TestIndexFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"index.html":      "root",
+		"docs/index.htm":  "docs",
+		"empty/notes.txt": "notes",
+	})
+
+	for name, fs := range fileSystems(dir) {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(fs, WithIndexFiles("index.html", "index.htm"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for target, want := range map[string]string{"/": "root", "/docs/": "docs", "/index.html": "root"} {
+				if rec := serve(h, target); rec.Code != http.StatusOK || rec.Body.String() != want {
+					t.Errorf("%s: got %d %q, want %q", target, rec.Code, rec.Body.String(), want)
+				}
+			}
+			rec := serve(h, "/docs?v=1")
+			if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "docs/?v=1" {
+				t.Errorf("/docs: got %d Location %q", rec.Code, rec.Header().Get("Location"))
+			}
+			if rec := serve(h, "/empty/"); rec.Code != http.StatusForbidden {
+				t.Errorf("/empty/: got %d, want 403", rec.Code)
+			}
+		})
+	}
+
+	if _, err := New(http.Dir(dir), WithIndexFiles("a/index.html")); err == nil {
+		t.Error("expected error for index file name containing a slash")
+	}
+}
+
+// TestListing tests HTML and JSON directory listings, sorting and hidden-file filtering.
+func TestListing(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"files/a.txt":     "a",
+		"files/big.bin":   "0123456789",
+		"files/.secret":   "s",
+		"files/sub/c.txt": "c",
+	})
+
+	for name, fs := range fileSystems(dir) {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(fs, WithListing(Listing{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := serve(h, "/files/?sort=size&order=desc", "Accept", "application/json")
+			if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+				t.Fatalf("json: got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+			}
+			var page ListingPage
+			if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, e := range page.Entries {
+				names = append(names, e.URL)
+			}
+			if got := strings.Join(names, " "); got != "./sub/ ./big.bin ./a.txt" {
+				t.Errorf("entries = %q", got)
+			}
+
+			rec = serve(h, "/files/")
+			if body := rec.Body.String(); !strings.Contains(body, `href="./a.txt"`) || strings.Contains(body, ".secret") {
+				t.Errorf("html listing: %s", body)
+			}
+		})
+	}
+
+	nonce := secure.NonceHeaders(secure.CSPConfig{})
+	h, err := New(http.Dir(dir), WithListing(Listing{ShowHidden: true}), WithDotfiles(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := serve(nonce(h), "/files/")
+	if body := rec.Body.String(); !strings.Contains(body, "<style nonce=") || !strings.Contains(body, ".secret") {
+		t.Errorf("nonce listing: %s", body)
+	}
+}
+
+// TestDeny tests that dotfiles and denied paths are answered with 404 unless explicitly allowed.
+func TestDeny(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env":                     "SECRET=1",
+		".git/config":              "[core]",
+		"config.php~":              "backup",
+		".well-known/security.txt": "contact",
+		".well-known/.git/HEAD":    "ref",
+		".git/info/notes.txt":      "notes",
+		".env.txt":                 "SECRET=1",
+		"readme.txt":               "readme",
+		"app.js":                   "ok",
+	})
+
+	for name, fs := range fileSystems(dir) {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(fs, WithDeny("*~"), WithAllow("/.well-known/", "*.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Allow patterns lift the dotfile rule only for the dot segments they spell out
+			for _, target := range []string{
+				"/.env", "/.git/config", "/.git/", "/config.php~", "/x/../.env",
+				"/.git/info/notes.txt", "/.env.txt", "/.well-known/.git/HEAD",
+			} {
+				if rec := serve(h, target); rec.Code != http.StatusNotFound {
+					t.Errorf("%s: got %d, want 404", target, rec.Code)
+				}
+			}
+			for _, target := range []string{"/app.js", "/.well-known/security.txt", "/readme.txt"} {
+				if rec := serve(h, target); rec.Code != http.StatusOK {
+					t.Errorf("%s: got %d, want 200", target, rec.Code)
+				}
+			}
+			if rec := serve(h, "/.well-known"); rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != ".well-known/" {
+				t.Errorf("/.well-known: got %d, Location %q", rec.Code, rec.Header().Get("Location"))
+			}
+		})
+	}
+
+	if _, err := New(http.Dir(dir), WithDeny("[")); err == nil {
+		t.Error("expected error for invalid deny pattern")
+	}
+}
+
+// linkDir is a generic http.FileSystem over a directory that reports symbolic links like fs.ReadLinkFS.
+type linkDir struct{ http.Dir }
+
+func (d linkDir) Lstat(name string) (os.FileInfo, error) {
+	return os.Lstat(filepath.Join(string(d.Dir), filepath.FromSlash(name)))
+}
+
+func (d linkDir) ReadLink(name string) (string, error) {
+	return os.Readlink(filepath.Join(string(d.Dir), filepath.FromSlash(name)))
+}
+
+// TestSymlinks tests the symlink policies on the fast path and on a generic file system reporting links.
+func TestSymlinks(t *testing.T) {
+	base := writeFiles(t, map[string]string{
+		"root/assets/app.js": "ok",
+		"root/.secrets/key":  "key",
+		"secret.txt":         "secret",
+	})
+	root := filepath.Join(base, "root")
+	for link, target := range map[string]string{
+		"inside.js":   "assets/app.js",
+		"relative.js": "../root/assets/app.js",
+		"escape.txt":  "../secret.txt",
+		"absolute":    filepath.Join(base, "secret.txt"),
+		"outdir":      "..",
+		"assets/up":   "..",
+		"cfg":         ".secrets",
+	} {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(link))); err != nil {
+			t.Skip("symlinks not supported:", err)
+		}
+	}
+
+	tests := []struct {
+		policy SymlinkPolicy
+		want   map[string]int
+	}{
+		{SymlinksWithinRoot, map[string]int{
+			"/assets/app.js": 200, "/inside.js": 200, "/assets/up/inside.js": 200, "/relative.js": 404,
+			"/escape.txt": 404, "/absolute": 404, "/outdir/secret.txt": 404, "/../secret.txt": 404, "/cfg/key": 404,
+		}},
+		{SymlinksDeny, map[string]int{
+			"/assets/app.js": 200, "/inside.js": 404, "/assets/up/inside.js": 404, "/escape.txt": 404,
+		}},
+		{SymlinksFollow, map[string]int{
+			"/inside.js": 200, "/escape.txt": 200, "/outdir/secret.txt": 200, "/../secret.txt": 404, "/cfg/key": 404,
+		}},
+	}
+	fileSystems := map[string]http.FileSystem{"dir": http.Dir(root), "generic": linkDir{http.Dir(root)}}
+	for name, fs := range fileSystems {
+		for _, tt := range tests {
+			h, err := New(fs, WithSymlinks(tt.policy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for target, want := range tt.want {
+				if rec := serve(h, target); rec.Code != want {
+					t.Errorf("%s policy %d %s: got %d, want %d", name, tt.policy, target, rec.Code, want)
+				}
+			}
+		}
+	}
+}
+
+// TestReleaseSwap tests that a swapped "current" symlink is served at once and a missing root answers 404.
+func TestReleaseSwap(t *testing.T) {
+	base := writeFiles(t, map[string]string{"releases/a/version.txt": "old", "releases/b/version.txt": "new"})
+	current := filepath.Join(base, "current")
+	if err := os.Symlink(filepath.Join("releases", "a"), current); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	h, err := New(http.Dir(current))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(h, "/version.txt"); rec.Body.String() != "old" {
+		t.Fatalf("before swap: got %q", rec.Body.String())
+	}
+
+	next := filepath.Join(base, "next")
+	if err := os.Symlink(filepath.Join("releases", "b"), next); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(next, current); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(h, "/version.txt"); rec.Body.String() != "new" {
+		t.Errorf("after swap: got %q", rec.Body.String())
+	}
+
+	h, err = New(http.Dir(filepath.Join(base, "missing")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(h, "/version.txt"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing root: got %d", rec.Code)
+	}
+}
+
+// TestNewFS tests serving an fs.FS with startup content ETags and a fixed modification time.
+func TestNewFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"public/app.js":     {Data: []byte("console.log(1)")},
+		"public/index.html": {Data: []byte("<app>")},
+		"public/.env":       {Data: []byte("SECRET=1")},
+	}
+	buildTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, err := NewFS(fsys, WithPrefix("/public"), WithModTime(buildTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(h, "/app.js")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || len(etag) != etagLength+2 {
+		t.Fatalf("app.js: got %d ETag %q", rec.Code, etag)
+	}
+	if got := rec.Header().Get("Last-Modified"); got != buildTime.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q", got)
+	}
+	if rec := serve(h, "/app.js", "If-None-Match", etag); rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: got %d, want 304", rec.Code)
+	}
+	if rec := serve(h, "/"); rec.Code != http.StatusOK || rec.Body.String() != "<app>" {
+		t.Errorf("index: got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := serve(h, "/.env"); rec.Code != http.StatusNotFound {
+		t.Errorf(".env: got %d, want 404", rec.Code)
+	}
+}
+
+// TestETag tests the ETag strategies with If-None-Match, If-Match and If-Range.
+func TestETag(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"app.js": "0123456789"})
+
+	for _, strategy := range []ETagStrategy{ETagContentHash, ETagSizeModTime, ETagInode} {
+		h, err := New(http.Dir(dir), WithETag(strategy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		etag := serve(h, "/app.js").Header().Get("ETag")
+		if !strings.HasPrefix(etag, `"`) {
+			t.Fatalf("strategy %d: ETag = %q", strategy, etag)
+		}
+
+		tests := []struct {
+			headers []string
+			want    int
+		}{
+			{[]string{"If-None-Match", etag}, http.StatusNotModified},
+			{[]string{"If-None-Match", `"other"`}, http.StatusOK},
+			{[]string{"If-Match", `"other"`}, http.StatusPreconditionFailed},
+			{[]string{"If-Match", etag}, http.StatusOK},
+			{[]string{"Range", "bytes=0-3", "If-Range", etag}, http.StatusPartialContent},
+			{[]string{"Range", "bytes=0-3", "If-Range", `"other"`}, http.StatusOK},
+		}
+		for _, tt := range tests {
+			if rec := serve(h, "/app.js", tt.headers...); rec.Code != tt.want {
+				t.Errorf("strategy %d %v: got %d, want %d", strategy, tt.headers, rec.Code, tt.want)
+			}
+		}
+	}
+
+	// Content hashes survive modification time resets and change with the content
+	h, err := New(http.Dir(dir), WithETag(ETagContentHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(dir, "app.js")
+	before := serve(h, "/app.js").Header().Get("ETag")
+	if err := os.Chtimes(p, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got := serve(h, "/app.js").Header().Get("ETag"); got != before {
+		t.Errorf("ETag changed after mtime reset: %q != %q", got, before)
+	}
+	if err := os.WriteFile(p, []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := serve(h, "/app.js").Header().Get("ETag"); got == before {
+		t.Error("ETag unchanged after content change")
+	}
+
+	// A file replaced by a rename with the same size and modification time is hashed again
+	stat, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fileInode(stat); !ok {
+		return
+	}
+	before = serve(h, "/app.js").Header().Get("ETag")
+	tmp := filepath.Join(dir, "app.js.tmp")
+	if err := os.WriteFile(tmp, []byte("CHANGED"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(tmp, stat.ModTime(), stat.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		t.Fatal(err)
+	}
+	if got := serve(h, "/app.js").Header().Get("ETag"); got == before {
+		t.Error("ETag unchanged after replacing the file with the same size and modification time")
+	}
+}
+
+// TestCrossOriginResourcePolicy tests the Cross-Origin-Resource-Policy header of served and cached files.
+func TestCrossOriginResourcePolicy(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"app.js": "ok"})
+
+	for name, opts := range map[string][]Option{
+		"direct": nil,
+		"cached": {WithMemoryCache(NewMemoryCache(MemoryCacheConfig{}))},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(http.Dir(dir), append(opts, WithCrossOriginResourcePolicy(secure.ResourcePolicySameSite))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for range 2 {
+				if got := serve(h, "/app.js").Header().Get("Cross-Origin-Resource-Policy"); got != "same-site" {
+					t.Errorf("Cross-Origin-Resource-Policy = %q, want same-site", got)
+				}
+			}
+		})
+	}
+
+	h, err := New(http.Dir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := serve(h, "/app.js").Header().Get("Cross-Origin-Resource-Policy"); got != "" {
+		t.Errorf("unset policy sent %q", got)
+	}
+	if _, err := New(http.Dir(dir), WithCrossOriginResourcePolicy("same-host")); err == nil {
+		t.Error("expected error for invalid Cross-Origin-Resource-Policy")
+	}
+}
+
+// TestFingerprints tests fingerprinted URLs, their immutable caching and stale fingerprints.
+func TestFingerprints(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"public/app.js":  "console.log(1)",
+		"public/LICENSE": "MIT",
+		"public/.env":    "SECRET=1",
+		"app.js":         "outside the prefix",
+	})
+	fingerprint := func(content string) string {
+		sum := sha512.Sum384([]byte(content))
+		return hex.EncodeToString(sum[:])[:16]
+	}
+
+	// The fingerprinter hashes through the file server, with its prefix and deny rules
+	fp := NewFingerprinter(http.Dir(dir), "/static")
+	h, err := New(http.Dir(dir), WithPrefix("/public"), WithFingerprints(fp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := template.Must(template.New("").Funcs(fp.FuncMap()).Parse(`<script src="{{asset "/app.js"}}"></script>`))
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	jsURL := "/static/app." + fingerprint("console.log(1)") + ".js"
+	if want := `<script src="` + jsURL + `"></script>`; buf.String() != want {
+		t.Errorf("template = %q, want %q", buf.String(), want)
+	}
+	licenseURL, err := fp.URL("LICENSE")
+	if want := "/static/LICENSE." + fingerprint("MIT"); err != nil || licenseURL != want {
+		t.Errorf("URL(LICENSE) = %q, %v, want %q", licenseURL, err, want)
+	}
+	if u, err := fp.URL("/.env"); err == nil {
+		t.Errorf("URL(/.env) = %q, want an error", u)
+	}
+
+	for target, want := range map[string]string{
+		strings.TrimPrefix(jsURL, "/static"):      "console.log(1)",
+		strings.TrimPrefix(licenseURL, "/static"): "MIT",
+	} {
+		rec := serve(h, target)
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("GET %s: %d %q, want %q", target, rec.Code, rec.Body.String(), want)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != "public, max-age=31536000, immutable" {
+			t.Errorf("GET %s: Cache-Control = %q", target, got)
+		}
+	}
+	if got := serve(h, "/app.js").Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("plain name: Cache-Control = %q, want no-cache", got)
+	}
+	if rec := serve(h, "/app.0123456789abcdef.js"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown fingerprint: got %d, want 404", rec.Code)
+	}
+
+	// Changing the content makes the old URL stale
+	if err := os.WriteFile(filepath.Join(dir, "public", "app.js"), []byte("console.log(2)"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(h, strings.TrimPrefix(jsURL, "/static")); rec.Code != http.StatusNotFound {
+		t.Errorf("stale fingerprint: got %d, want 404", rec.Code)
+	}
+	if u, _ := fp.URL("/app.js"); u != "/static/app."+fingerprint("console.log(2)")+".js" {
+		t.Errorf("URL after change = %q", u)
+	}
+
+	if _, err := New(http.Dir(dir), WithFingerprints(fp)); err == nil {
+		t.Error("New accepted a fingerprinter used by another file server")
+	}
+}
+
+// TestIntegrity tests SRI strings, CSP hash sources and precomputing through the file server.
+func TestIntegrity(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"public/app.js":        "alert('Hello, world.');",
+		"public/css/empty.css": "",
+		"public/.env":          "SECRET=1",
+	})
+	const (
+		appSRI   = "sha384-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO"
+		emptySRI = "sha384-OLBgp1GsljhM2TJ+sbHjaiH9txEUvgdDTAzHv2P24donTt6/529l+9Ua0vFImLlb"
+	)
+
+	i := NewIntegrity(http.Dir(dir))
+	if _, err := New(http.Dir(dir), WithPrefix("/public"), WithIntegrity(i)); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.Precompute(); err != nil {
+		t.Fatalf("Precompute: %v", err)
+	}
+	if got, err := i.Get("app.js"); err != nil || got != appSRI {
+		t.Errorf("Get(app.js) = %q, %v, want %q", got, err, appSRI)
+	}
+	sources, err := i.HashSources("/app.js", "/css/empty.css")
+	if want := "'" + appSRI + "' '" + emptySRI + "'"; err != nil || strings.Join(sources, " ") != want {
+		t.Errorf("HashSources = %q, %v, want %q", sources, err, want)
+	}
+	if _, err := i.HashSources("/app.js", "/.env"); err == nil {
+		t.Error("HashSources hashed a denied file")
+	}
+	if _, err := i.Get("/css"); err == nil {
+		t.Error("Get hashed a directory")
+	}
+
+	tmpl := template.Must(template.New("").Funcs(i.FuncMap()).Parse(`<script src="/static/app.js" integrity="{{integrity "/app.js"}}"></script>`))
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<script src="/static/app.js" integrity="` + appSRI + `"></script>`; html.UnescapeString(buf.String()) != want {
+		t.Errorf("template = %q, want %q", buf.String(), want)
+	}
+
+	if _, err := New(http.Dir(dir), WithIntegrity(i)); err == nil {
+		t.Error("New accepted an integrity used by another file server")
+	}
+}
+
+// TestCachePolicy tests the Cache-Control value and the additional headers of cache policies.
+func TestCachePolicy(t *testing.T) {
+	tests := []struct {
+		policy CachePolicy
+		want   string
+	}{
+		{CachePolicy{}, ""},
+		{CachePolicy{Public: true, MaxAge: time.Hour, Immutable: true}, "public, max-age=3600, immutable"},
+		{CachePolicy{Private: true, NoCache: true, MustRevalidate: true}, "private, no-cache, must-revalidate"},
+		{CachePolicy{MaxAge: time.Minute, SMaxAge: time.Hour, StaleWhileRevalidate: 30 * time.Second, StaleIfError: time.Hour},
+			"max-age=60, s-maxage=3600, stale-while-revalidate=30, stale-if-error=3600"},
+		{CachePolicy{NoStore: true, Public: true, MaxAge: time.Hour}, "no-store"},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.String(); got != tt.want {
+			t.Errorf("%+v: got %q, want %q", tt.policy, got, tt.want)
+		}
+	}
+
+	header := http.Header{}
+	CachePolicy{MaxAge: time.Hour, Expires: true, CDNMaxAge: 24 * time.Hour}.apply(header)
+	if expires, err := http.ParseTime(header.Get("Expires")); err != nil || time.Until(expires) < 59*time.Minute {
+		t.Errorf("Expires = %q", header.Get("Expires"))
+	}
+	if got := header.Get("CDN-Cache-Control"); got != "max-age=86400" {
+		t.Errorf("CDN-Cache-Control = %q", got)
+	}
+	header = http.Header{}
+	CachePolicy{NoStore: true, MaxAge: time.Hour, Expires: true, CDNMaxAge: time.Hour}.apply(header)
+	if header.Get("Expires") != "" || header.Get("CDN-Cache-Control") != "" {
+		t.Errorf("no-store sent %v", header)
+	}
+}
+
+// TestCacheRules tests the rule matchers, first-match order and the fallback to the default policy.
+func TestCacheRules(t *testing.T) {
+	pages := CachePolicy{NoCache: true}
+	assets := CachePolicy{Public: true, MaxAge: 365 * 24 * time.Hour, Immutable: true}
+	images := CachePolicy{Public: true, MaxAge: 7 * 24 * time.Hour}
+	fonts := CachePolicy{Public: true, MaxAge: 30 * 24 * time.Hour}
+	rules := []CacheRule{
+		{Match: MatchGlob("*.html"), Policy: pages},
+		{Match: MatchGlob("/assets/"), Policy: assets},
+		{Match: MatchContentType("image/*", "Application/PDF"), Policy: images},
+		{Match: MatchExt(".WOFF2", ".ttf"), Policy: fonts},
+		{Match: nil, Policy: CachePolicy{NoStore: true}},
+	}
+
+	tests := []struct {
+		name string
+		want CachePolicy
+		ok   bool
+	}{
+		{"/index.html", pages, true},
+		{"/assets/index.html", pages, true},
+		{"/assets/logo.png", assets, true},
+		{"/img/logo.png", images, true},
+		{"/docs/manual.pdf", images, true},
+		{"/fonts/inter.woff2", fonts, true},
+		{"/fonts/INTER.TTF", fonts, true},
+		{"/app.js", CachePolicy{}, false},
+		{"/LICENSE", CachePolicy{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := cachePolicyFor(rules, tt.name); got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %q %v, want %q %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	dir := writeFiles(t, map[string]string{"index.html": "home", "app.js": "ok"})
+	h, err := New(http.Dir(dir), WithCachePolicy(CachePolicy{Public: true, MaxAge: time.Hour}), WithCacheRules(rules...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for target, want := range map[string]string{"/index.html": "no-cache", "/app.js": "public, max-age=3600"} {
+		if got := serve(h, target).Header().Get("Cache-Control"); got != want {
+			t.Errorf("%s: Cache-Control = %q, want %q", target, got, want)
+		}
+	}
+
+	if _, err := New(http.Dir(dir), WithCacheRules(CacheRule{Match: MatchGlob("*.css", "[")})); err == nil {
+		t.Error("expected error for invalid cache rule pattern")
+	}
+}
+
+// TestMemoryCache tests cache hits, compressed variants, revalidation, eviction and watching.
+func TestMemoryCache(t *testing.T) {
+	css := strings.Repeat("body{margin:0}", 100)
+	dir := writeFiles(t, map[string]string{"app.css": css, "logo.bin": "0123456789", "big.bin": strings.Repeat("x", 2048)})
+
+	cache := NewMemoryCache(MemoryCacheConfig{MaxFileSize: 1600, CheckInterval: time.Nanosecond})
+	h, err := New(http.Dir(dir), WithMemoryCache(cache), WithETag(ETagContentHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := serve(h, "/app.css")
+	gz := serve(h, "/app.css", "Accept-Encoding", "gzip, br;q=0")
+	if plain.Body.String() != css || plain.Header().Get("Content-Encoding") != "" {
+		t.Errorf("plain: got %q encoding", plain.Header().Get("Content-Encoding"))
+	}
+	if gz.Header().Get("Content-Encoding") != "gzip" || gz.Body.Len() >= len(css) || gz.Header().Get("ETag") == plain.Header().Get("ETag") {
+		t.Errorf("gzip: got encoding %q, %d bytes, ETag %q", gz.Header().Get("Content-Encoding"), gz.Body.Len(), gz.Header().Get("ETag"))
+	}
+	if rec := serve(h, "/app.css", "If-None-Match", plain.Header().Get("ETag")); rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: got %d, want 304", rec.Code)
+	}
+	if rec := serve(h, "/big.bin"); rec.Body.Len() != 2048 {
+		t.Errorf("uncached big file: got %d bytes", rec.Body.Len())
+	}
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Entries != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+	for header, want := range map[string]string{
+		"*":                 "gzip",
+		"*, gzip;q=0":       "",
+		"gzip;q=0, *":       "",
+		"*;q=0, gzip;q=0.5": "gzip",
+		"br":                "",
+	} {
+		if got := serve(h, "/app.css", "Accept-Encoding", header).Header().Get("Content-Encoding"); got != want {
+			t.Errorf("Accept-Encoding %q: got encoding %q, want %q", header, got, want)
+		}
+	}
+
+	// Modified files are picked up by the modification time check
+	if err := os.WriteFile(filepath.Join(dir, "app.css"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(h, "/app.css"); rec.Body.String() != "changed" {
+		t.Errorf("after change: got %q", rec.Body.String())
+	}
+
+	// The least recently used file is evicted once MaxSize is exceeded
+	small := NewMemoryCache(MemoryCacheConfig{MaxSize: 12, CheckInterval: -1})
+	h, err = New(http.Dir(dir), WithMemoryCache(small))
+	if err != nil {
+		t.Fatal(err)
+	}
+	serve(h, "/app.css")
+	serve(h, "/logo.bin")
+	if stats := small.Stats(); stats.Evictions != 1 || stats.Entries != 1 || stats.Size != 10 {
+		t.Errorf("eviction stats = %+v", stats)
+	}
+
+	// Watch invalidates changed files without a modification time check
+	if err := small.Watch(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer small.Close()
+	if err := os.WriteFile(filepath.Join(dir, "logo.bin"), []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for serve(h, "/logo.bin").Body.String() != "new" {
+		if time.Now().After(deadline) {
+			t.Fatal("watcher did not invalidate logo.bin")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Renaming a directory invalidates the files below it
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docs", "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(h, "/docs/a.txt"); rec.Body.String() != "a" {
+		t.Fatalf("docs/a.txt: got %q", rec.Body.String())
+	}
+	if err := os.Rename(filepath.Join(dir, "docs"), filepath.Join(dir, "archive")); err != nil {
+		t.Fatal(err)
+	}
+	for serve(h, "/docs/a.txt").Code != http.StatusNotFound {
+		if time.Now().After(deadline) {
+			t.Fatal("watcher did not invalidate the renamed directory")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestLiveReload tests script injection with the CSP nonce and reload events over Server-Sent Events.
+func TestLiveReload(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"index.html": "<html><body><h1>hi</h1></body></html>", "app.css": "body{}"})
+	cache := NewMemoryCache(MemoryCacheConfig{CheckInterval: -1})
+	lr, err := NewLiveReload(LiveReloadConfig{Dir: dir, Caches: []*MemoryCache{cache}, Debounce: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lr.Close()
+	h, err := New(http.Dir(dir), WithMemoryCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(secure.NonceHeaders(secure.CSPConfig{})(lr.Middleware(h)))
+	defer srv.Close()
+
+	rec := serve(secure.NonceHeaders(secure.CSPConfig{})(lr.Middleware(h)), "/", "Accept-Encoding", "gzip")
+	body := rec.Body.String()
+	if !strings.Contains(body, `<script nonce="`) || !strings.HasSuffix(body, "</script></body></html>") {
+		t.Errorf("injected page: %s", body)
+	}
+	// Without NonceHeaders the script is injected without a nonce
+	// NonceHeaders가 없으면 nonce 없이 스크립트를 삽입
+	if body := serve(lr.Middleware(h), "/").Body.String(); !strings.Contains(body, `<script>new EventSource(`) {
+		t.Errorf("page without nonce: %s", body)
+	}
+	if rec := serve(lr.Middleware(h), "/app.css"); rec.Body.String() != "body{}" {
+		t.Errorf("css: got %q", rec.Body.String())
+	}
+
+	resp, err := http.Get(srv.URL + "/__livereload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+	events := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		var received string
+		for {
+			n, err := resp.Body.Read(buf)
+			received += string(buf[:n])
+			if strings.Contains(received, "event: reload\ndata: /app.css\n") {
+				events <- received
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "app.css"), []byte("body{color:red}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload event")
+	}
+	if rec := serve(h, "/app.css"); rec.Body.String() != "body{color:red}" {
+		t.Errorf("cache not invalidated: got %q", rec.Body.String())
+	}
+}
+
+// TestErrorPages tests error pages from the file system and custom error handlers.
+func TestErrorPages(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"404.html": "<h1>gone</h1>", "private/notes.txt": "x"})
+	forbidden := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("no entry"))
+	})
+
+	for name, fs := range fileSystems(dir) {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(fs, WithCachePolicy(CachePolicy{Public: true, MaxAge: time.Hour}),
+				WithErrorPage(http.StatusNotFound, "404.html"), WithErrorHandler(http.StatusForbidden, forbidden))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, target := range []string{"/missing.js", "/.env"} {
+				rec := serve(h, target)
+				if rec.Code != http.StatusNotFound || rec.Body.String() != "<h1>gone</h1>" {
+					t.Errorf("%s: got %d %q", target, rec.Code, rec.Body.String())
+				}
+				if rec.Header().Get("Content-Type") != "text/html; charset=utf-8" || rec.Header().Get("Cache-Control") != "no-store" {
+					t.Errorf("%s: headers %v", target, rec.Header())
+				}
+			}
+			rec := serve(h, "/private/")
+			if rec.Code != http.StatusForbidden || rec.Body.String() != "no entry" || rec.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("/private/: got %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+			}
+
+			// A handler that writes nothing still sends the status
+			// 아무것도 쓰지 않는 핸들러도 상태를 전송
+			silent, err := New(fs, WithErrorHandler(http.StatusNotFound, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec := serve(silent, "/missing.js"); rec.Code != http.StatusNotFound || rec.Body.Len() != 0 || rec.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("silent handler: got %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+			}
+		})
+	}
+
+	if _, err := New(http.Dir(dir), WithErrorPage(http.StatusOK, "200.html")); err == nil {
+		t.Error("expected error for non-error status")
+	}
+}
+
+// TestOverlay tests overriding files across roots, merged listings and SPA mode.
+func TestOverlay(t *testing.T) {
+	theme := writeFiles(t, map[string]string{"index.html": "<app>", "css/site.css": "theme", "css/base.css": "base"})
+	tenant := writeFiles(t, map[string]string{"css/site.css": "tenant", "logo.png": "png"})
+
+	for name, fs := range map[string]http.FileSystem{
+		"dir":     Overlay(http.Dir(tenant), http.Dir(theme)),
+		"generic": Overlay(struct{ http.FileSystem }{http.Dir(tenant)}, struct{ http.FileSystem }{http.Dir(theme)}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(fs, WithListing(Listing{}), WithSPA("index.html"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for target, want := range map[string]string{
+				"/css/site.css": "tenant", "/css/base.css": "base", "/logo.png": "png", "/users/42": "<app>",
+			} {
+				if rec := serve(h, target); rec.Code != http.StatusOK || rec.Body.String() != want {
+					t.Errorf("%s: got %d %q, want %q", target, rec.Code, rec.Body.String(), want)
+				}
+			}
+
+			var page ListingPage
+			if err := json.Unmarshal(serve(h, "/css/", "Accept", "application/json").Body.Bytes(), &page); err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Entries) != 2 {
+				t.Errorf("merged listing: %+v", page.Entries)
+			}
+		})
+	}
+}
+
+// TestArchive tests serving zip, tar and tar.gz archives, including Range requests and gzip passthrough.
+func TestArchive(t *testing.T) {
+	files := map[string]string{"index.html": strings.Repeat("<p>archive</p>", 50), "assets/app.js": "0123456789"}
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	for name, content := range files {
+		method := zip.Deflate
+		if name == "assets/app.js" {
+			method = zip.Store
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	var tarBuf, tgzBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gw := gzip.NewWriter(&tgzBuf)
+	gw.Write(tarBuf.Bytes())
+	gw.Close()
+
+	for name, archive := range map[string][]byte{"zip": zipBuf.Bytes(), "tar": tarBuf.Bytes(), "tar.gz": tgzBuf.Bytes()} {
+		t.Run(name, func(t *testing.T) {
+			fs, err := NewArchiveFS(bytes.NewReader(archive), int64(len(archive)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			h, err := New(fs, WithListing(Listing{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if rec := serve(h, "/"); rec.Code != http.StatusOK || rec.Body.String() != files["index.html"] {
+				t.Errorf("index: got %d %q", rec.Code, rec.Body.String())
+			}
+			if rec := serve(h, "/assets/app.js", "Range", "bytes=2-4"); rec.Code != http.StatusPartialContent || rec.Body.String() != "234" {
+				t.Errorf("range: got %d %q", rec.Code, rec.Body.String())
+			}
+			if rec := serve(h, "/assets/"); !strings.Contains(rec.Body.String(), "app.js") {
+				t.Errorf("listing of implicit directory: %q", rec.Body.String())
+			}
+
+			rec := serve(h, "/index.html", "Accept-Encoding", "gzip")
+			if name != "zip" {
+				if rec.Header().Get("Content-Encoding") != "" {
+					t.Errorf("unexpected Content-Encoding %q", rec.Header().Get("Content-Encoding"))
+				}
+				return
+			}
+			if rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+				t.Fatalf("passthrough headers: %v", rec.Header())
+			}
+			zr, err := gzip.NewReader(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body, err := io.ReadAll(zr); err != nil || string(body) != files["index.html"] {
+				t.Errorf("passthrough body: %q, %v", body, err)
+			}
+			if rec := serve(h, "/index.html", "Range", "bytes=5-7"); rec.Code != http.StatusPartialContent || rec.Body.String() != "chi" {
+				t.Errorf("range over deflated entry: got %d %q", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestArchiveRejects tests that encrypted zip entries, sparse tar entries and oversized tar.gz archives are rejected.
+func TestArchiveRejects(t *testing.T) {
+	var encrypted bytes.Buffer
+	zw := zip.NewWriter(&encrypted)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "secret.txt", Method: zip.Store, Flags: 0x1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("ciphertext"))
+	zw.Close()
+
+	var gnuSparse bytes.Buffer
+	tw := tar.NewWriter(&gnuSparse)
+	tw.WriteHeader(&tar.Header{Name: "sparse.bin", Typeflag: tar.TypeGNUSparse, Format: tar.FormatGNU})
+	tw.Close()
+
+	// tar.Writer drops sparse PAX records, so the extended header is written as a regular file and retyped
+	var paxSparse bytes.Buffer
+	records := "22 GNU.sparse.major=1\n22 GNU.sparse.minor=0\n30 GNU.sparse.name=sparse.bin\n"
+	tw = tar.NewWriter(&paxSparse)
+	tw.WriteHeader(&tar.Header{Name: "PaxHeaders/sparse.bin", Size: int64(len(records)), Typeflag: tar.TypeReg})
+	tw.Write([]byte(records))
+	data := append([]byte("0\n"), make([]byte, 510)...)
+	tw.WriteHeader(&tar.Header{Name: "sparse.bin", Size: int64(len(data)), Typeflag: tar.TypeReg})
+	tw.Write(data)
+	tw.Close()
+	header := paxSparse.Bytes()[:512]
+	header[156] = tar.TypeXHeader
+	copy(header[148:156], "        ")
+	sum := 0
+	for _, c := range header {
+		sum += int(c)
+	}
+	copy(header[148:156], fmt.Sprintf("%06o\x00 ", sum))
+
+	for name, archive := range map[string][]byte{"encrypted zip": encrypted.Bytes(), "GNU sparse": gnuSparse.Bytes(), "PAX sparse": paxSparse.Bytes()} {
+		if _, err := NewArchiveFS(bytes.NewReader(archive), int64(len(archive))); err == nil {
+			t.Errorf("%s: archive accepted", name)
+		}
+	}
+
+	var tarBuf, tgzBuf bytes.Buffer
+	tw = tar.NewWriter(&tarBuf)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: 10, Typeflag: tar.TypeReg})
+		tw.Write([]byte("0123456789"))
+	}
+	tw.Close()
+	gw := gzip.NewWriter(&tgzBuf)
+	gw.Write(tarBuf.Bytes())
+	gw.Close()
+	tgz := tgzBuf.Bytes()
+	for limit, ok := range map[int64]bool{15: false, 20: true, 0: true, -1: false} {
+		_, err := NewArchiveFS(bytes.NewReader(tgz), int64(len(tgz)), WithMaxUnpackedSize(limit))
+		if (err == nil) != ok {
+			t.Errorf("limit %d: err = %v", limit, err)
+		}
+	}
+}
+
+// TestRanges tests range limits: coalescing, maximum count, minimum size, single-range fallback and disabled paths.
+func TestRanges(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"video.bin": "0123456789abcdefghij", "live/stream.bin": "0123456789"})
+	h, err := New(http.Dir(dir), WithRanges(Ranges{MaxRanges: 2, Coalesce: true, MinRangeSize: 3, Disable: []string{"/live/"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Overlapping ranges are coalesced into one
+	rec := serve(h, "/video.bin", "Range", "bytes=0-4,2-7")
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "01234567" || rec.Header().Get("Content-Range") != "bytes 0-7/20" {
+		t.Errorf("coalesced: got %d %q %q", rec.Code, rec.Header().Get("Content-Range"), rec.Body.String())
+	}
+	// Too many ranges and ranges shorter than the minimum are served in full
+	for _, ranges := range []string{"bytes=0-3,5-8,10-13", "bytes=0-1,10-15"} {
+		if rec := serve(h, "/video.bin", "Range", ranges); rec.Code != http.StatusOK || rec.Body.Len() != 20 {
+			t.Errorf("%s: got %d", ranges, rec.Code)
+		}
+	}
+	if rec := serve(h, "/video.bin", "Range", "bytes=0-3,10-13"); !strings.HasPrefix(rec.Header().Get("Content-Type"), "multipart/byteranges") {
+		t.Errorf("multipart: got %q", rec.Header().Get("Content-Type"))
+	}
+	// Single ranges are not subject to the minimum size
+	if rec := serve(h, "/video.bin", "Range", "bytes=-1"); rec.Code != http.StatusPartialContent || rec.Body.String() != "j" {
+		t.Errorf("suffix: got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(h, "/live/stream.bin", "Range", "bytes=0-1")
+	if rec.Code != http.StatusOK || rec.Header().Get("Accept-Ranges") != "none" {
+		t.Errorf("disabled: got %d, Accept-Ranges %q", rec.Code, rec.Header().Get("Accept-Ranges"))
+	}
+
+	h, err = New(http.Dir(dir), WithRanges(Ranges{DisableMultipart: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = serve(h, "/video.bin", "Range", "bytes=12-13,2-4")
+	if rec.Code != http.StatusPartialContent || rec.Header().Get("Content-Range") != "bytes 2-13/20" {
+		t.Errorf("spanning range: got %d %q", rec.Code, rec.Header().Get("Content-Range"))
+	}
+}
+
+// TestThrottle tests bandwidth throttling and the concurrency limit of large transfers.
+func TestThrottle(t *testing.T) {
+	// Hex text compresses to about half its size, so its gzip variant is still a large transfer
+	var page string
+	for sum := sha256.Sum256(nil); len(page) < 8192; sum = sha256.Sum256(sum[:]) {
+		page += hex.EncodeToString(sum[:])
+	}
+	dir := writeFiles(t, map[string]string{"large.bin": strings.Repeat("x", 65000), "small.txt": "small", "page.html": page})
+	cache := NewMemoryCache(MemoryCacheConfig{})
+	defer cache.Close()
+	h, err := New(http.Dir(dir), WithMemoryCache(cache), WithThrottle(Throttle{ConnectionRate: 50000, MaxConcurrent: 1, MinSize: 1024}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	start := time.Now()
+	go func() { done <- serve(h, "/large.bin") }()
+	for len(h.(*handler).throttle.slots) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	rec := serve(h, "/large.bin")
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("concurrent transfer: got %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := serve(h, "/small.txt"); rec.Code != http.StatusOK {
+		t.Errorf("small file: got %d", rec.Code)
+	}
+	// Revalidations and small ranges of the large file send few bytes and need no slot
+	// 대용량 파일의 재검증과 작은 범위는 적은 바이트만 보내므로 자리가 필요 없음
+	if rec := serve(h, "/large.bin", "If-Modified-Since", time.Now().UTC().Format(http.TimeFormat)); rec.Code != http.StatusNotModified {
+		t.Errorf("revalidation: got %d", rec.Code)
+	}
+	if rec := serve(h, "/large.bin", "Range", "bytes=0-9"); rec.Code != http.StatusPartialContent || rec.Body.String() != "xxxxxxxxxx" {
+		t.Errorf("small range: got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := serve(h, "/large.bin", "Range", "bytes=0-4999"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("large range: got %d", rec.Code)
+	}
+	rec = serve(h, "/page.html", "Accept-Encoding", "gzip")
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Content-Encoding") != "" || rec.Header().Get("ETag") != "" {
+		t.Errorf("rejected gzip transfer: got %d, headers %v", rec.Code, rec.Header())
+	}
+
+	// One second of burst, then 15000 bytes at 50000 bytes per second
+	rec = <-done
+	if rec.Code != http.StatusOK || rec.Body.Len() != 65000 {
+		t.Errorf("throttled transfer: got %d, %d bytes", rec.Code, rec.Body.Len())
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("throttled transfer took %v", elapsed)
+	}
+	if rec := serve(h, "/large.bin", "Range", "bytes=0-9"); rec.Code != http.StatusPartialContent {
+		t.Errorf("after release: got %d", rec.Code)
+	}
+}
+
+// TestSignedURLs tests signed URLs with expiry, path prefixes and client IP binding.
+func TestSignedURLs(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"reports/q1.pdf": "q1", "reports/q2.pdf": "q2"})
+	signer := &URLSigner{SecretKey: []byte("secret")}
+	h, err := New(http.Dir(dir), WithSignedURLs(signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/files/", http.StripPrefix("/files", h))
+
+	sign := func(target string, ttl time.Duration, opts SignOptions) string {
+		signed, err := signer.Sign(target, time.Now().Add(ttl), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	valid := sign("/files/reports/q1.pdf", time.Hour, SignOptions{})
+	prefixed := sign("/files/reports/q1.pdf", time.Hour, SignOptions{Prefix: "/files/reports/"})
+
+	for target, want := range map[string]int{
+		"/files/reports/q1.pdf":               http.StatusForbidden,
+		valid:                                 http.StatusOK,
+		strings.Replace(valid, "q1", "q2", 1): http.StatusForbidden,
+		sign("/files/reports/q1.pdf", -time.Minute, SignOptions{}):                      http.StatusForbidden,
+		strings.Replace(prefixed, "q1", "q2", 1):                                        http.StatusOK,
+		sign("/files/reports/q1.pdf", time.Hour, SignOptions{ClientIP: "192.0.2.1"}):    http.StatusOK,
+		sign("/files/reports/q1.pdf", time.Hour, SignOptions{ClientIP: "198.51.100.7"}): http.StatusForbidden,
+	} {
+		if rec := serve(mux, target); rec.Code != want {
+			t.Errorf("%s: got %d, want %d", target, rec.Code, want)
+		}
+	}
+
+	if _, err := signer.Sign("/files/other.pdf", time.Now(), SignOptions{Prefix: "/files/reports/"}); err == nil {
+		t.Error("expected error for a path outside the signing prefix")
+	}
+}
+
+// TestNewPrefix tests WithPrefix and mounting on a net/http ServeMux.
+func TestNewPrefix(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"public/app.js": "ok"})
+
+	for name, fs := range fileSystems(dir) {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(fs, WithPrefix("/public"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			mux := http.NewServeMux()
+			mux.Handle("/static/", http.StripPrefix("/static", h))
+
+			if rec := serve(mux, "/static/app.js"); rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+				t.Errorf("got %d %q", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestNewInvalid tests that New reports invalid configurations.
+func TestNewInvalid(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Error("expected error for nil file system")
+	}
+	if _, err := New(http.Dir("."), WithCrossOriginResourcePolicy("anyone")); err == nil {
+		t.Error("expected error for invalid Cross-Origin-Resource-Policy")
+	}
+	if _, err := New(http.Dir("."), WithRanges(Ranges{Disable: []string{"["}})); err == nil {
+		t.Error("expected error for invalid range glob pattern")
+	}
+}
+
+// TestRun tests the chi convenience wrapper.
+func TestRun(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"app.js": "ok"})
+	r := chi.NewRouter()
+	Run(r, "/static", http.Dir(dir), "", -1)
+
+	rec := serve(r, "/static/app.js")
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("got %d %v", rec.Code, rec.Header())
+	}
+	if rec := serve(r, "/static"); rec.Code != http.StatusMovedPermanently {
+		t.Errorf("got %d, want 301", rec.Code)
+	}
+
+	// Options passed by the caller win over the legacy arguments
+	// 호출자가 전달한 옵션이 기존 인자보다 우선
+	policy := WithCachePolicy(CachePolicy{Public: true, MaxAge: time.Hour})
+	Run(r, "/s", http.Dir(dir), "", 0, policy)
+	RunFS(r, "/fs", os.DirFS(dir), -1, policy)
+	for _, target := range []string{"/s/app.js", "/fs/app.js"} {
+		if rec := serve(r, target); rec.Header().Get("Cache-Control") != "public, max-age=3600" {
+			t.Errorf("%s: got %d %v", target, rec.Code, rec.Header())
+		}
+	}
+}
+
+// TestSPA tests the SPA fallback for client-side routes and real 404s for missing assets.
+func TestSPA(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"index.html": "<app>", "app.js": "js"})
+
+	for name, fs := range fileSystems(dir) {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(fs, WithSPA("index.html"), WithCachePolicy(CachePolicy{Public: true, MaxAge: time.Hour}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, target := range []string{"/users/42", "/index.html"} {
+				rec := serve(h, target)
+				if rec.Code != http.StatusOK || rec.Body.String() != "<app>" {
+					t.Errorf("%s: got %d %q", target, rec.Code, rec.Body.String())
+				}
+			}
+			if got := serve(h, "/users/42").Header().Get("Cache-Control"); got != "no-cache" {
+				t.Errorf("fallback Cache-Control = %q, want no-cache", got)
+			}
+			if rec := serve(h, "/report.pdf", "Accept", "text/html"); rec.Code != http.StatusOK {
+				t.Errorf("html navigation: got %d, want 200", rec.Code)
+			}
+			if rec := serve(h, "/missing.js", "Accept", "*/*"); rec.Code != http.StatusNotFound {
+				t.Errorf("missing asset: got %d, want 404", rec.Code)
+			}
+			if rec := serve(h, "/app.js"); rec.Body.String() != "js" {
+				t.Errorf("existing asset: got %q", rec.Body.String())
+			}
+		})
+	}
+}
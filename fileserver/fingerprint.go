@@ -0,0 +1,118 @@
+package fileserver
+
+import (
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// fingerprintLength is the number of hex characters of the content hash embedded in fingerprinted names.
+// fingerprintLength는 지문이 포함된 이름에 들어가는 콘텐츠 해시의 16진수 문자 수입니다.
+const fingerprintLength = 16
+
+// immutableCachePolicy is the cache policy for fingerprinted URLs, whose content never changes.
+// immutableCachePolicy는 콘텐츠가 절대 바뀌지 않는 지문 URL을 위한 캐시 정책입니다.
+var immutableCachePolicy = CachePolicy{Public: true, MaxAge: 365 * 24 * time.Hour, Immutable: true}
+
+// revalidateCachePolicy is the cache policy for unfingerprinted names, which must be revalidated on every use.
+// revalidateCachePolicy는 사용할 때마다 재검증해야 하는, 지문이 없는 이름을 위한 캐시 정책입니다.
+var revalidateCachePolicy = CachePolicy{NoCache: true}
+
+// Fingerprinter maps logical asset names to content-hashed URLs (e.g. "/app.js" to "/static/app.3f2a9c1b5d7e0846.js")
+// and, when passed to the file server with WithFingerprints, serves those URLs with immutable caching.
+//
+// Fingerprinter는 논리적 에셋 이름을 콘텐츠 해시가 포함된 URL로 매핑하며(예: "/app.js"를 "/static/app.3f2a9c1b5d7e0846.js"로),
+// WithFingerprints로 파일 서버에 전달하면 해당 URL을 immutable 캐싱으로 제공합니다.
+type Fingerprinter struct {
+	digests digestBinding
+	urlPath string
+}
+
+// NewFingerprinter creates a Fingerprinter for the given file system. urlPath is the URL path the file server
+// is mounted at (e.g. "/static") and is prepended to the URLs returned by URL. Once the Fingerprinter is passed
+// to a file server with WithFingerprints, names are resolved and hashed through that file server, with its prefix,
+// deny rules and symlink policy, and fs is no longer used. A Fingerprinter can be used by one file server only.
+//
+// NewFingerprinter는 주어진 파일 시스템을 위한 Fingerprinter를 생성합니다. urlPath는 파일 서버가 마운트된
+// URL 경로(예: "/static")이며, URL이 반환하는 URL 앞에 붙습니다. WithFingerprints로 파일 서버에 전달되면
+// 이름은 해당 파일 서버의 접두사, 거부 규칙, 심볼릭 링크 정책에 따라 그 파일 서버를 통해 확인되고 해시되며,
+// fs는 더 이상 사용되지 않습니다. Fingerprinter는 하나의 파일 서버에서만 사용할 수 있습니다.
+func NewFingerprinter(fs http.FileSystem, urlPath string) *Fingerprinter {
+	return &Fingerprinter{digests: digestBinding{own: newDigestCache(fs)}, urlPath: urlPath}
+}
+
+// URL returns the fingerprinted URL of the named asset.
+// URL은 지정된 에셋의 지문이 포함된 URL을 반환합니다.
+func (f *Fingerprinter) URL(name string) (string, error) {
+	name = path.Clean("/" + name)
+	hash, err := f.hash(name)
+	if err != nil {
+		return "", err
+	}
+	dir, base := path.Split(name)
+	ext := path.Ext(base)
+	return path.Join("/", f.urlPath, dir, strings.TrimSuffix(base, ext)+"."+hash+ext), nil
+}
+
+// FuncMap returns a template.FuncMap with an "asset" function, for use as
+// <script src="{{asset "/app.js"}}"></script>.
+//
+// FuncMap은 "asset" 함수를 포함한 template.FuncMap을 반환하며, 다음과 같이 사용합니다.
+// <script src="{{asset "/app.js"}}"></script>
+func (f *Fingerprinter) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": f.URL}
+}
+
+// resolve maps a fingerprinted name back to its logical name. It reports false if name carries
+// no fingerprint or the fingerprint does not match the current content.
+// resolve는 지문이 포함된 이름을 논리적 이름으로 되돌립니다. name에 지문이 없거나
+// 지문이 현재 콘텐츠와 일치하지 않으면 false를 반환합니다.
+func (f *Fingerprinter) resolve(name string) (string, bool) {
+	dir, base := path.Split(name)
+	ext := path.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+
+	logical, hash := "", ""
+	if h := path.Ext(stem); isFingerprint(h) {
+		// "app.3f2a9c1b5d7e0846.js"
+		logical, hash = dir+strings.TrimSuffix(stem, h)+ext, h[1:]
+	} else if isFingerprint(ext) {
+		// "LICENSE.3f2a9c1b5d7e0846"
+		logical, hash = dir+stem, ext[1:]
+	} else {
+		return "", false
+	}
+
+	current, err := f.hash(logical)
+	if err != nil || current != hash {
+		return "", false
+	}
+	return logical, true
+}
+
+// hash returns the fingerprint of the named file.
+// hash는 지정된 파일의 지문을 반환합니다.
+func (f *Fingerprinter) hash(name string) (string, error) {
+	sum, err := f.digests.cache().sum(name)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum)[:fingerprintLength], nil
+}
+
+// isFingerprint reports whether ext is a "." followed by a fingerprint.
+// isFingerprint는 ext가 "." 뒤에 지문이 오는 형태인지 여부를 반환합니다.
+func isFingerprint(ext string) bool {
+	if len(ext) != fingerprintLength+1 {
+		return false
+	}
+	for _, c := range ext[1:] {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package fileserver
+
+import (
+	"encoding/base64"
+	"html/template"
+	"net/http"
+)
+
+// Integrity computes Subresource Integrity (SRI) strings for files in the http.FileSystem served by the file server.
+// Digests are SHA-384, cached per file and recomputed when the file's inode, modification time or size changes.
+//
+// Integrity는 파일 서버가 제공하는 http.FileSystem 내 파일의 하위 리소스 무결성(SRI) 문자열을 계산합니다.
+// 다이제스트는 SHA-384이며 파일별로 캐시되고, 파일의 아이노드, 수정 시간 또는 크기가 바뀌면 다시 계산됩니다.
+type Integrity struct {
+	digests digestBinding
+}
+
+// NewIntegrity creates an Integrity for the given file system. Pass it to the file server with WithIntegrity,
+// so names are resolved and hashed through the file server, with its prefix, deny rules and symlink policy,
+// and share its digest cache; fs is then no longer used. An Integrity can be used by one file server only.
+//
+// NewIntegrity는 주어진 파일 시스템을 위한 Integrity를 생성합니다. WithIntegrity로 파일 서버에 전달하면
+// 이름이 해당 파일 서버의 접두사, 거부 규칙, 심볼릭 링크 정책에 따라 그 파일 서버를 통해 확인되고 해시되며
+// 다이제스트 캐시를 공유합니다. 이후 fs는 더 이상 사용되지 않습니다. Integrity는 하나의 파일 서버에서만
+// 사용할 수 있습니다.
+func NewIntegrity(fs http.FileSystem) *Integrity {
+	return &Integrity{digests: digestBinding{own: newDigestCache(fs)}}
+}
+
+// Precompute walks the whole file system and computes the digest of every file,
+// so the first requests do not pay for hashing.
+//
+// Precompute는 전체 파일 시스템을 탐색하여 모든 파일의 다이제스트를 계산하므로,
+// 첫 요청에서 해싱 비용이 발생하지 않습니다.
+func (i *Integrity) Precompute() error {
+	return i.digests.cache().walk("/", nil)
+}
+
+// Get returns the integrity attribute value of the named file, e.g. "sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC".
+//
+// Get은 지정된 파일의 integrity 속성 값을 반환합니다(예: "sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC").
+func (i *Integrity) Get(name string) (string, error) {
+	sum, err := i.digests.cache().sum(name)
+	if err != nil {
+		return "", err
+	}
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum), nil
+}
+
+// HashSources returns the digests of the named files as CSP hash sources (e.g. "'sha384-...'"),
+// ready to be appended to secure.CSPConfig.ScriptSrc or StyleSrc.
+//
+// HashSources는 지정된 파일들의 다이제스트를 CSP 해시 소스(예: "'sha384-...'")로 반환하며,
+// secure.CSPConfig.ScriptSrc나 StyleSrc에 바로 추가할 수 있습니다.
+func (i *Integrity) HashSources(names ...string) ([]string, error) {
+	sources := make([]string, 0, len(names))
+	for _, name := range names {
+		v, err := i.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, "'"+v+"'")
+	}
+	return sources, nil
+}
+
+// FuncMap returns a template.FuncMap with an "integrity" function, for use as
+// <script src="/static/app.js" integrity="{{integrity "/app.js"}}" nonce="{{.Nonce}}"></script>.
+//
+// FuncMap은 "integrity" 함수를 포함한 template.FuncMap을 반환하며, 다음과 같이 사용합니다.
+// <script src="/static/app.js" integrity="{{integrity "/app.js"}}" nonce="{{.Nonce}}"></script>
+func (i *Integrity) FuncMap() template.FuncMap {
+	return template.FuncMap{"integrity": i.Get}
+}
@@ -0,0 +1,99 @@
+package fileserver
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ioLinkFS exposes the symbolic links of an fs.ReadLinkFS, such as os.DirFS, through linkFileSystem.
+// ioLinkFS는 os.DirFS와 같은 fs.ReadLinkFS의 심볼릭 링크를 linkFileSystem으로 노출합니다.
+type ioLinkFS struct {
+	http.FileSystem
+	fsys fs.ReadLinkFS
+}
+
+// Lstat returns the file info of the named file without following a final symbolic link.
+// Lstat은 마지막 심볼릭 링크를 따라가지 않고 지정된 파일의 정보를 반환합니다.
+func (l ioLinkFS) Lstat(name string) (os.FileInfo, error) {
+	return l.fsys.Lstat(ioName(name))
+}
+
+// ReadLink returns the target of the named symbolic link.
+// ReadLink는 지정된 심볼릭 링크의 대상을 반환합니다.
+func (l ioLinkFS) ReadLink(name string) (string, error) {
+	return l.fsys.ReadLink(ioName(name))
+}
+
+// ioName converts a rooted slash-separated name into an fs.FS name.
+// ioName은 루트로 시작하는 슬래시 구분 이름을 fs.FS 이름으로 변환합니다.
+func ioName(name string) string {
+	name = strings.Trim(name, "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// NewFS creates an http.Handler that serves static files from an fs.FS such as embed.FS, with the same
+// behavior and options as New. Since the files of an fs.FS do not change while the program runs, the content
+// of every file is hashed at startup and sent as a strong ETag (ETagContentHash, unless WithETag selects another
+// strategy), so conditional requests work even though embed.FS reports zero modification times.
+// Use WithModTime to also send a Last-Modified header.
+//
+// NewFS는 embed.FS와 같은 fs.FS의 정적 파일을 제공하는 http.Handler를 생성하며, New와 동일한 동작과 옵션을 가집니다.
+// fs.FS의 파일은 프로그램 실행 중 바뀌지 않으므로 시작 시 모든 파일의 콘텐츠를 해시하여 강한 ETag로 전송하고
+// (WithETag로 다른 전략을 선택하지 않는 한 ETagContentHash),
+// 따라서 embed.FS가 수정 시간을 0으로 보고하더라도 조건부 요청이 동작합니다.
+// Last-Modified 헤더도 전송하려면 WithModTime을 사용하세요.
+//
+// Example:
+//
+//	//go:embed public
+//	var public embed.FS
+//
+//	h, err := fileserver.NewFS(public, fileserver.WithPrefix("/public"), fileserver.WithModTime(buildTime))
+func NewFS(fsys fs.FS, opts ...Option) (http.Handler, error) {
+	if fsys == nil {
+		return nil, errors.New("fileserver: nil file system")
+	}
+	var hfs http.FileSystem = http.FS(fsys)
+	if links, ok := fsys.(fs.ReadLinkFS); ok {
+		hfs = ioLinkFS{FileSystem: hfs, fsys: links}
+	}
+
+	h, err := New(hfs, append([]Option{WithETag(ETagContentHash)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	if digests := h.(*handler).digests; digests != nil {
+		if err := digests.walk("/", nil); err != nil {
+			return nil, fmt.Errorf("fileserver: hashing files: %w", err)
+		}
+	}
+	return h, nil
+}
+
+// RunFS is the fs.FS counterpart of Run: it mounts NewFS on the given Chi router at urlPath.
+// Use fs.Sub or WithPrefix to serve a subdirectory. WithCachePolicy in opts takes precedence over
+// cacheMaxAgeSeconds. It panics if the configuration is invalid.
+//
+// RunFS는 Run의 fs.FS 버전으로, 주어진 Chi 라우터의 urlPath에 NewFS를 마운트합니다.
+// 하위 디렉토리를 제공하려면 fs.Sub나 WithPrefix를 사용하세요. opts의 WithCachePolicy가
+// cacheMaxAgeSeconds보다 우선합니다. 설정이 유효하지 않으면 panic을 발생시킵니다.
+func RunFS(r *chi.Mux, urlPath string, fsys fs.FS, cacheMaxAgeSeconds int, opts ...Option) {
+	if strings.ContainsAny(urlPath, "{}*") {
+		panic(fmt.Sprintf("FileServer does not permit URL parameters in urlPath: %s", urlPath))
+	}
+	opts = append([]Option{WithCachePolicy(legacyCachePolicy(cacheMaxAgeSeconds))}, opts...)
+	h, err := NewFS(fsys, opts...)
+	if err != nil {
+		panic(err.Error())
+	}
+	mount(r, urlPath, h)
+}
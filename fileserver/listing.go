@@ -0,0 +1,228 @@
+package fileserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DevNewbie1826/webUtil/secure"
+)
+
+// listingCachePolicy is the cache policy of directory listings, which change whenever the directory does.
+// listingCachePolicy는 디렉토리가 바뀔 때마다 바뀌는 디렉토리 리스팅의 캐시 정책입니다.
+var listingCachePolicy = CachePolicy{NoCache: true}
+
+// Listing configures the directory listing enabled by WithListing.
+// Listing은 WithListing으로 활성화되는 디렉토리 리스팅을 설정합니다.
+type Listing struct {
+	// ShowHidden includes entries whose name starts with "." in the listing.
+	// Dotfiles are only listed if they are served too, see WithDotfiles and WithAllow; denied paths are never listed.
+	// ShowHidden은 이름이 "."으로 시작하는 항목을 리스팅에 포함합니다.
+	// Dotfile은 제공도 되는 경우에만 나열되며(WithDotfiles, WithAllow 참고), 거부된 경로는 절대 나열되지 않습니다.
+	ShowHidden bool
+	// Template renders the HTML listing and is executed with a *ListingPage.
+	// If nil, a built-in template is used.
+	// Template은 HTML 리스팅을 렌더링하며 *ListingPage로 실행됩니다.
+	// nil이면 내장 템플릿이 사용됩니다.
+	Template *template.Template
+}
+
+// ListingPage is the data a listing template is executed with and the JSON representation of a listing.
+// ListingPage는 리스팅 템플릿 실행에 사용되는 데이터이자 리스팅의 JSON 표현입니다.
+type ListingPage struct {
+	// Path is the path of the directory inside the file system, ending in "/".
+	// Path는 파일 시스템 내부 디렉토리의 경로이며, "/"로 끝납니다.
+	Path string `json:"path"`
+	// Entries are the sorted directory entries.
+	// Entries는 정렬된 디렉토리 항목입니다.
+	Entries []ListingEntry `json:"entries"`
+	// Sort is the sort key ("name", "size" or "mtime") and Order the sort order ("asc" or "desc").
+	// Sort는 정렬 키("name", "size", "mtime")이고 Order는 정렬 순서("asc", "desc")입니다.
+	Sort  string `json:"sort"`
+	Order string `json:"order"`
+	// Nonce is the CSP nonce set by secure.NonceHeaders, or "" if there is none.
+	// Nonce는 secure.NonceHeaders가 설정한 CSP nonce이며, 없으면 ""입니다.
+	Nonce string `json:"-"`
+}
+
+// ListingEntry describes a file or directory in a listing.
+// ListingEntry는 리스팅의 파일 또는 디렉토리를 나타냅니다.
+type ListingEntry struct {
+	Name string `json:"name"`
+	// URL is the entry's URL relative to the listing, ending in "/" for directories.
+	// URL은 리스팅 기준의 상대 URL이며, 디렉토리는 "/"로 끝납니다.
+	URL     string    `json:"url"`
+	IsDir   bool      `json:"isDir"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// SortURL returns the query string that sorts the listing by key, toggling the order if it is already sorted by key.
+// SortURL은 key로 리스팅을 정렬하는 쿼리 문자열을 반환하며, 이미 key로 정렬되어 있으면 순서를 뒤집습니다.
+func (p *ListingPage) SortURL(key string) string {
+	order := "asc"
+	if p.Sort == key && p.Order == "asc" {
+		order = "desc"
+	}
+	return "?sort=" + key + "&order=" + order
+}
+
+// defaultListingTemplate is the built-in HTML listing. Its inline styles carry the CSP nonce, if any.
+// defaultListingTemplate은 내장 HTML 리스팅입니다. 인라인 스타일에는 CSP nonce가 있으면 포함됩니다.
+var defaultListingTemplate = template.Must(template.New("listing").Funcs(template.FuncMap{
+	"size": formatSize,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Index of {{.Path}}</title>
+<style{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
+body{font-family:system-ui,sans-serif;margin:2rem;color:#222}
+h1{font-size:1.25rem;font-weight:600}
+table{border-collapse:collapse;width:100%;max-width:60rem}
+th,td{text-align:left;padding:.35rem .75rem;border-bottom:1px solid #eee}
+th a{color:inherit}
+td.num{text-align:right;font-variant-numeric:tabular-nums}
+a{color:#0a58ca;text-decoration:none}
+a:hover{text-decoration:underline}
+</style>
+</head>
+<body>
+<h1>Index of {{.Path}}</h1>
+<table>
+<thead><tr><th><a href="{{.SortURL "name"}}">Name</a></th><th class="num"><a href="{{.SortURL "size"}}">Size</a></th><th><a href="{{.SortURL "mtime"}}">Modified</a></th></tr></thead>
+<tbody>
+{{if ne .Path "/"}}<tr><td><a href="../">../</a></td><td class="num">-</td><td></td></tr>
+{{end}}{{range .Entries}}<tr><td><a href="{{.URL}}">{{.Name}}{{if .IsDir}}/{{end}}</a></td><td class="num">{{if .IsDir}}-{{else}}{{size .Size}}{{end}}</td><td>{{.ModTime.UTC.Format "2006-01-02 15:04:05"}}</td></tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+// serveListing renders the listing of the named directory, as JSON if the client accepts application/json
+// and as HTML otherwise. The query parameters "sort" (name, size, mtime) and "order" (asc, desc) select the order.
+// serveListing은 지정된 디렉토리의 리스팅을 렌더링하며, 클라이언트가 application/json을 허용하면 JSON으로,
+// 그렇지 않으면 HTML로 응답합니다. 쿼리 파라미터 "sort"(name, size, mtime)와 "order"(asc, desc)로 순서를 정합니다.
+func (h *handler) serveListing(w http.ResponseWriter, r *http.Request, name string) {
+	infos, err := h.readDir(name)
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+
+	page := &ListingPage{Path: strings.TrimSuffix(name, "/") + "/", Entries: make([]ListingEntry, 0, len(infos))}
+	for _, info := range infos {
+		if !h.cfg.listing.ShowHidden && strings.HasPrefix(info.Name(), ".") || h.cfg.isDenied(path.Join(name, info.Name())) {
+			continue
+		}
+		if info.Mode()&os.ModeSymlink != 0 && h.cfg.symlinks == SymlinksDeny {
+			continue
+		}
+		entry := ListingEntry{
+			Name:    info.Name(),
+			URL:     (&url.URL{Path: "./" + info.Name()}).String(),
+			IsDir:   info.IsDir(),
+			ModTime: info.ModTime(),
+		}
+		if entry.IsDir {
+			entry.URL += "/"
+		} else {
+			entry.Size = info.Size()
+		}
+		page.Entries = append(page.Entries, entry)
+	}
+	page.Sort, page.Order = sortEntries(page.Entries, r.URL.Query().Get("sort"), r.URL.Query().Get("order"))
+
+	var buf bytes.Buffer
+	contentType := "text/html; charset=utf-8"
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		contentType = "application/json"
+		err = json.NewEncoder(&buf).Encode(page)
+	} else {
+		page.Nonce, _ = secure.LookupNonce(r.Context())
+		tmpl := h.cfg.listing.Template
+		if tmpl == nil {
+			tmpl = defaultListingTemplate
+		}
+		err = tmpl.Execute(&buf, page)
+	}
+	if err != nil {
+		h.serveError(w, r, err)
+		return
+	}
+
+	listingCachePolicy.apply(w.Header())
+	h.cfg.applyHeaders(w.Header())
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	w.Write(buf.Bytes())
+}
+
+// readDir returns the entries of the named directory.
+// readDir는 지정된 디렉토리의 항목을 반환합니다.
+func (h *handler) readDir(name string) ([]os.FileInfo, error) {
+	f, err := h.openFile(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Readdir(-1)
+}
+
+// sortEntries sorts entries by key and order, directories first, and returns the normalized key and order.
+// sortEntries는 디렉토리를 먼저 두고 key와 order로 항목을 정렬하며, 정규화된 key와 order를 반환합니다.
+func sortEntries(entries []ListingEntry, key, order string) (string, string) {
+	var less func(a, b ListingEntry) bool
+	switch key {
+	case "size":
+		less = func(a, b ListingEntry) bool { return a.Size < b.Size }
+	case "mtime":
+		less = func(a, b ListingEntry) bool { return a.ModTime.Before(b.ModTime) }
+	default:
+		key = "name"
+		less = func(a, b ListingEntry) bool { return a.Name < b.Name }
+	}
+	if order != "desc" {
+		order = "asc"
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+		if order == "desc" {
+			a, b = b, a
+		}
+		if less(a, b) || less(b, a) {
+			return less(a, b)
+		}
+		return a.Name < b.Name
+	})
+	return key, order
+}
+
+// formatSize formats a byte count for humans, e.g. "1.5 KiB".
+// formatSize는 바이트 수를 사람이 읽기 쉬운 형태로 변환합니다(예: "1.5 KiB").
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64) + " " + string("KMGTPE"[exp]) + "iB"
+}
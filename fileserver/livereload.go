@@ -0,0 +1,279 @@
+package fileserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DevNewbie1826/webUtil/secure"
+)
+
+// LiveReloadConfig configures a LiveReload.
+// LiveReloadConfig는 LiveReload를 설정합니다.
+type LiveReloadConfig struct {
+	// Dir is the directory to watch, usually the directory the file server serves.
+	// Dir은 감시할 디렉토리이며, 보통 파일 서버가 제공하는 디렉토리입니다.
+	Dir string
+	// Path is the URL path of the Server-Sent Events endpoint. Defaults to "/__livereload".
+	// Path는 Server-Sent Events 엔드포인트의 URL 경로입니다. 기본값은 "/__livereload"입니다.
+	Path string
+	// Caches are invalidated before a reload event is sent.
+	// Caches는 리로드 이벤트를 전송하기 전에 무효화됩니다.
+	Caches []*MemoryCache
+	// Debounce coalesces bursts of changes, such as a build writing many files, into one reload. Defaults to 100ms.
+	// Debounce는 많은 파일을 쓰는 빌드처럼 연속된 변경을 하나의 리로드로 합칩니다. 기본값은 100ms입니다.
+	Debounce time.Duration
+}
+
+// LiveReload watches a directory during development and tells connected browsers to reload when a file changes.
+// Browsers connect to the Server-Sent Events endpoint through the script returned by Script or injected by Middleware.
+// Do not enable it in production.
+//
+// LiveReload는 개발 중 디렉토리를 감시하고, 파일이 바뀌면 연결된 브라우저에 리로드를 지시합니다.
+// 브라우저는 Script가 반환하거나 Middleware가 삽입한 스크립트를 통해 Server-Sent Events 엔드포인트에 연결합니다.
+// 운영 환경에서는 활성화하지 마세요.
+type LiveReload struct {
+	config  LiveReloadConfig
+	watcher watcher
+
+	mu      sync.Mutex
+	clients map[chan string]struct{}
+	timer   *time.Timer
+	pending string
+}
+
+// NewLiveReload starts watching config.Dir. Stop it with Close.
+// NewLiveReload는 config.Dir 감시를 시작합니다. Close로 중지합니다.
+func NewLiveReload(config LiveReloadConfig) (*LiveReload, error) {
+	if config.Dir == "" {
+		return nil, errors.New("fileserver: live reload requires a directory")
+	}
+	if config.Path == "" {
+		config.Path = "/__livereload"
+	}
+	if config.Debounce <= 0 {
+		config.Debounce = 100 * time.Millisecond
+	}
+	l := &LiveReload{config: config, clients: make(map[chan string]struct{})}
+	w, err := newWatcher(config.Dir, l.changed)
+	if err != nil {
+		return nil, fmt.Errorf("fileserver: %w", err)
+	}
+	l.watcher = w
+	return l, nil
+}
+
+// Close stops watching and disconnects all clients.
+// Close는 감시를 중지하고 모든 클라이언트의 연결을 끊습니다.
+func (l *LiveReload) Close() error {
+	err := l.watcher.Close()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	for ch := range l.clients {
+		close(ch)
+		delete(l.clients, ch)
+	}
+	return err
+}
+
+// changed invalidates the caches and schedules a reload event.
+// changed는 캐시를 무효화하고 리로드 이벤트를 예약합니다.
+func (l *LiveReload) changed(name string, tree bool) {
+	for _, c := range l.config.Caches {
+		c.changed(name, tree)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = name
+	if l.timer == nil {
+		l.timer = time.AfterFunc(l.config.Debounce, l.broadcast)
+	} else {
+		l.timer.Reset(l.config.Debounce)
+	}
+}
+
+// broadcast sends the pending change to every connected client.
+// broadcast는 대기 중인 변경 사항을 연결된 모든 클라이언트에 전송합니다.
+func (l *LiveReload) broadcast() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for ch := range l.clients {
+		select {
+		case ch <- l.pending:
+		default:
+			// The client has a reload pending already
+			// 클라이언트에 이미 대기 중인 리로드가 있음
+		}
+	}
+}
+
+// ServeHTTP serves the Server-Sent Events endpoint, sending a "reload" event with the changed path.
+// ServeHTTP는 Server-Sent Events 엔드포인트를 제공하며, 변경된 경로와 함께 "reload" 이벤트를 전송합니다.
+func (l *LiveReload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	ch := make(chan string, 1)
+	l.mu.Lock()
+	l.clients[ch] = struct{}{}
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.clients, ch)
+		l.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 1000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case name, ok := <-ch:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "event: reload\ndata: %s\n\n", strings.NewReplacer("\r", "", "\n", "").Replace(name))
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// Script returns the script element that connects to the endpoint and reloads the page on changes.
+// When secure.NonceHeaders runs before it, the element carries its nonce so it passes a nonce-based CSP;
+// the policy's connect-src must allow the endpoint (e.g. 'self'). Without a nonce the attribute is left out.
+//
+// Script는 엔드포인트에 연결하고 변경 시 페이지를 리로드하는 script 요소를 반환합니다.
+// secure.NonceHeaders가 앞서 실행되면 그 nonce를 포함하므로 nonce 기반 CSP를 통과하며,
+// 정책의 connect-src는 엔드포인트를 허용해야 합니다(예: 'self'). Nonce가 없으면 속성을 생략합니다.
+func (l *LiveReload) Script(r *http.Request) template.HTML {
+	attr := ""
+	if nonce, ok := secure.LookupNonce(r.Context()); ok {
+		attr = ` nonce="` + template.HTMLEscapeString(nonce) + `"`
+	}
+	return template.HTML(`<script` + attr + `>` +
+		`new EventSource(` + strconv.Quote(l.config.Path) + `).addEventListener("reload",function(){location.reload()});` +
+		`</script>`)
+}
+
+// FuncMap returns a template.FuncMap with a "livereload" function, for use as {{livereload .Request}}
+// before the closing body tag.
+//
+// FuncMap은 "livereload" 함수를 포함한 template.FuncMap을 반환하며, 닫는 body 태그 앞에서
+// {{livereload .Request}}와 같이 사용합니다.
+func (l *LiveReload) FuncMap() template.FuncMap {
+	return template.FuncMap{"livereload": l.Script}
+}
+
+// Middleware serves the endpoint at config.Path and injects Script into HTML responses of next,
+// such as pages served by the file server.
+//
+// Middleware는 config.Path에서 엔드포인트를 제공하고, 파일 서버가 제공하는 페이지와 같은
+// next의 HTML 응답에 Script를 삽입합니다.
+func (l *LiveReload) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == l.config.Path {
+			l.ServeHTTP(w, r)
+			return
+		}
+
+		// Ask for uncompressed, complete responses so the script can be inserted
+		// 스크립트를 삽입할 수 있도록 압축되지 않은 전체 응답을 요청
+		inner := r.Clone(r.Context())
+		for _, header := range []string{"Accept-Encoding", "Range", "If-Range", "If-None-Match", "If-Modified-Since"} {
+			inner.Header.Del(header)
+		}
+
+		iw := &injectWriter{ResponseWriter: w}
+		next.ServeHTTP(iw, inner)
+		if iw.inject {
+			iw.finish(l.Script(r))
+		}
+	})
+}
+
+// injectWriter buffers successful HTML responses so a script can be inserted before the closing body tag.
+// injectWriter는 닫는 body 태그 앞에 스크립트를 삽입할 수 있도록 성공한 HTML 응답을 버퍼링합니다.
+type injectWriter struct {
+	http.ResponseWriter
+	buf         bytes.Buffer
+	wroteHeader bool
+	inject      bool
+	status      int
+}
+
+// WriteHeader decides whether the response is buffered for injection.
+// WriteHeader는 응답을 삽입을 위해 버퍼링할지 결정합니다.
+func (w *injectWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	contentType := w.Header().Get("Content-Type")
+	w.inject = code == http.StatusOK && strings.HasPrefix(contentType, "text/html") && w.Header().Get("Content-Encoding") == ""
+	if !w.inject {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write buffers the body of injected responses and passes any other body through.
+// Write는 삽입 대상 응답의 본문을 버퍼링하고 그 외 본문은 그대로 전달합니다.
+func (w *injectWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.inject {
+		return w.buf.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+// Unwrap은 http.ResponseController를 위해 내부 http.ResponseWriter를 반환합니다.
+func (w *injectWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish writes the buffered response with script inserted before "</body>", or appended if there is none.
+// finish는 버퍼링된 응답을 "</body>" 앞에 script를 삽입하여 쓰며, 없으면 끝에 추가합니다.
+func (w *injectWriter) finish(script template.HTML) {
+	body := w.buf.Bytes()
+	i := bytes.LastIndex(bytes.ToLower(body), []byte("</body>"))
+	if i < 0 {
+		i = len(body)
+	}
+
+	h := w.ResponseWriter.Header()
+	h.Del("Content-Length")
+	h.Del("ETag")
+	h.Set("Cache-Control", "no-store")
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(body[:i])
+	w.ResponseWriter.Write([]byte(script))
+	w.ResponseWriter.Write(body[i:])
+}
@@ -0,0 +1,445 @@
+package fileserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"container/list"
+	"crypto/sha512"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DevNewbie1826/webUtil/compress"
+)
+
+// MemoryCacheConfig configures a MemoryCache. Zero values select the defaults.
+// MemoryCacheConfig는 MemoryCache를 설정합니다. 제로 값은 기본값을 선택합니다.
+type MemoryCacheConfig struct {
+	// MaxFileSize is the largest file that is cached. Defaults to 64 KiB.
+	// MaxFileSize는 캐시되는 가장 큰 파일 크기입니다. 기본값은 64 KiB입니다.
+	MaxFileSize int64
+	// MaxSize bounds the total size of cached contents, including compressed variants. Defaults to 32 MiB.
+	// The least recently used files are evicted first.
+	// MaxSize는 압축된 변형을 포함한 캐시 콘텐츠의 전체 크기를 제한합니다. 기본값은 32 MiB입니다.
+	// 가장 오래전에 사용된 파일부터 제거됩니다.
+	MaxSize int64
+	// CheckInterval is how often a cached file's modification time and size are checked against the file system.
+	// Defaults to 2 seconds; a negative value disables the check, e.g. when using Watch or an immutable fs.FS.
+	// CheckInterval은 캐시된 파일의 수정 시간과 크기를 파일 시스템과 비교하는 주기입니다.
+	// 기본값은 2초이며, 음수 값은 검사를 비활성화합니다(예: Watch나 변경되지 않는 fs.FS를 사용할 때).
+	CheckInterval time.Duration
+}
+
+// CacheStats are the counters of a MemoryCache.
+// CacheStats는 MemoryCache의 카운터입니다.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Entries and Size are the number of cached files and their total size in bytes.
+	// Entries와 Size는 캐시된 파일 수와 전체 크기(바이트)입니다.
+	Entries int
+	Size    int64
+}
+
+// MemoryCache is a bounded LRU cache of small files, their metadata and their compressed variants.
+// Pass it to the file server with WithMemoryCache. A MemoryCache must not be shared between file servers.
+// MemoryCache는 작은 파일과 그 메타데이터, 압축된 변형을 담는 크기 제한 LRU 캐시입니다.
+// WithMemoryCache로 파일 서버에 전달하세요. MemoryCache는 여러 파일 서버 간에 공유하면 안 됩니다.
+type MemoryCache struct {
+	config MemoryCacheConfig
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	size    int64
+
+	hits, misses, evictions atomic.Uint64
+
+	watchMu  sync.Mutex
+	watchers []watcher
+}
+
+// cacheEntry is a cached file. Its fields are immutable once the entry is stored, except checked.
+// cacheEntry는 캐시된 파일입니다. 저장된 후에는 checked를 제외한 필드가 변경되지 않습니다.
+type cacheEntry struct {
+	name        string
+	content     []byte
+	variants    map[string][]byte
+	contentType string
+	modTime     time.Time
+	size        int64
+	etag        string
+	checked     atomic.Int64
+}
+
+// cost returns the number of bytes accounted for the entry.
+// cost는 항목에 대해 계산되는 바이트 수를 반환합니다.
+func (e *cacheEntry) cost() int64 {
+	n := int64(len(e.content))
+	for _, v := range e.variants {
+		n += int64(len(v))
+	}
+	return n
+}
+
+// NewMemoryCache creates a MemoryCache.
+// NewMemoryCache는 MemoryCache를 생성합니다.
+func NewMemoryCache(config MemoryCacheConfig) *MemoryCache {
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = 64 << 10
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = 32 << 20
+	}
+	if config.CheckInterval == 0 {
+		config.CheckInterval = 2 * time.Second
+	}
+	return &MemoryCache{config: config, lru: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Stats returns the current counters of the cache.
+// Stats는 캐시의 현재 카운터를 반환합니다.
+func (c *MemoryCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   len(c.entries),
+		Size:      c.size,
+	}
+}
+
+// Invalidate removes the named file from the cache. Names of precompressed siblings ("app.js.gz", "app.js.br")
+// invalidate the file they belong to.
+// Invalidate는 지정된 파일을 캐시에서 제거합니다. 미리 압축된 형제 파일의 이름("app.js.gz", "app.js.br")은
+// 해당 원본 파일을 무효화합니다.
+func (c *MemoryCache) Invalidate(name string) {
+	name = path.Clean("/" + name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(name)
+	for _, enc := range precompressedEncodings {
+		if base, ok := strings.CutSuffix(name, enc.ext); ok {
+			c.remove(base)
+		}
+	}
+}
+
+// InvalidateDir removes every file below the named directory from the cache, e.g. after the directory
+// was removed or renamed. InvalidateDir("/") is equivalent to Purge.
+// InvalidateDir는 디렉토리가 삭제되거나 이름이 바뀐 경우처럼 지정된 디렉토리 아래의 모든 파일을 캐시에서 제거합니다.
+// InvalidateDir("/")는 Purge와 같습니다.
+func (c *MemoryCache) InvalidateDir(dir string) {
+	dir = path.Clean("/" + dir)
+	if dir == "/" {
+		c.Purge()
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(dir)
+	for name := range c.entries {
+		if strings.HasPrefix(name, dir+"/") {
+			c.remove(name)
+		}
+	}
+}
+
+// changed invalidates the cache for a change reported by a watcher.
+// changed는 감시자가 알린 변경에 대해 캐시를 무효화합니다.
+func (c *MemoryCache) changed(name string, tree bool) {
+	if tree {
+		c.InvalidateDir(name)
+		return
+	}
+	c.Invalidate(name)
+}
+
+// Purge removes every file from the cache.
+// Purge는 캐시에서 모든 파일을 제거합니다.
+func (c *MemoryCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	clear(c.entries)
+	c.size = 0
+}
+
+// Watch invalidates cached files as soon as they change below dir, which must be the directory the file server
+// serves (including any prefix). It uses inotify on Linux and polling elsewhere. Stop watching with Close.
+// Watch는 dir 아래의 파일이 바뀌는 즉시 캐시된 파일을 무효화하며, dir은 파일 서버가 제공하는 디렉토리(접두사 포함)여야 합니다.
+// Linux에서는 inotify를, 그 외 환경에서는 폴링을 사용합니다. Close로 감시를 중지합니다.
+func (c *MemoryCache) Watch(dir string) error {
+	w, err := newWatcher(dir, c.changed)
+	if err != nil {
+		return err
+	}
+	c.watchMu.Lock()
+	c.watchers = append(c.watchers, w)
+	c.watchMu.Unlock()
+	return nil
+}
+
+// Close stops all watchers started by Watch.
+// Close는 Watch로 시작된 모든 감시자를 중지합니다.
+func (c *MemoryCache) Close() error {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+	var firstErr error
+	for _, w := range c.watchers {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	c.watchers = nil
+	return firstErr
+}
+
+// get returns the cached entry of name, or nil on a miss. Entries due for a check are validated with stat
+// and dropped if the file changed.
+// get은 name의 캐시된 항목을 반환하며, 없으면 nil을 반환합니다. 검사 시기가 된 항목은 stat으로 검증되며
+// 파일이 바뀌었으면 제거됩니다.
+func (c *MemoryCache) get(name string, stat func(name string) (os.FileInfo, error)) *cacheEntry {
+	c.mu.Lock()
+	el, ok := c.entries[name]
+	if ok {
+		c.lru.MoveToFront(el)
+	}
+	c.mu.Unlock()
+	if !ok {
+		c.misses.Add(1)
+		return nil
+	}
+
+	e := el.Value.(*cacheEntry)
+	if interval := c.config.CheckInterval; interval > 0 {
+		now := time.Now()
+		if checked := e.checked.Load(); now.UnixNano()-checked >= int64(interval) {
+			info, err := stat(name)
+			if err != nil || !info.ModTime().Equal(e.modTime) || info.Size() != e.size {
+				c.mu.Lock()
+				if c.entries[name] == el {
+					c.remove(name)
+				}
+				c.mu.Unlock()
+				c.misses.Add(1)
+				return nil
+			}
+			e.checked.Store(now.UnixNano())
+		}
+	}
+	c.hits.Add(1)
+	return e
+}
+
+// add stores e, evicting the least recently used entries to stay within MaxSize.
+// add는 e를 저장하며, MaxSize를 넘지 않도록 가장 오래전에 사용된 항목을 제거합니다.
+func (c *MemoryCache) add(e *cacheEntry) {
+	cost := e.cost()
+	if cost > c.config.MaxSize {
+		return
+	}
+	e.checked.Store(time.Now().UnixNano())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(e.name)
+	for c.size+cost > c.config.MaxSize {
+		oldest := c.lru.Back()
+		c.remove(oldest.Value.(*cacheEntry).name)
+		c.evictions.Add(1)
+	}
+	c.entries[e.name] = c.lru.PushFront(e)
+	c.size += cost
+}
+
+// remove deletes the named entry. The caller must hold c.mu.
+// remove는 지정된 항목을 삭제합니다. 호출자는 c.mu를 보유해야 합니다.
+func (c *MemoryCache) remove(name string) {
+	if el, ok := c.entries[name]; ok {
+		c.lru.Remove(el)
+		delete(c.entries, name)
+		c.size -= el.Value.(*cacheEntry).cost()
+	}
+}
+
+// precompressedEncodings are the content codings served from the cache, in order of preference,
+// with the extension of their precompressed sibling files.
+// precompressedEncodings는 캐시에서 제공되는 콘텐츠 코딩을 선호 순서대로 나열하며,
+// 미리 압축된 형제 파일의 확장자를 함께 가집니다.
+var precompressedEncodings = []struct {
+	coding, ext string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// cacheFile reads the opened file f into a cache entry and stores it. It returns nil if the file is too large.
+// Precompressed siblings ("app.js.br", "app.js.gz") are cached alongside; compressible files without a gzip
+// sibling are gzip-compressed once.
+// cacheFile은 열린 파일 f를 캐시 항목으로 읽어 저장합니다. 파일이 너무 크면 nil을 반환합니다.
+// 미리 압축된 형제 파일("app.js.br", "app.js.gz")도 함께 캐시되며, gzip 형제 파일이 없는 압축 가능한 파일은
+// 한 번 gzip으로 압축됩니다.
+func (h *handler) cacheFile(c *MemoryCache, name string, f http.File, stat os.FileInfo) (*cacheEntry, error) {
+	if stat.Size() > c.config.MaxFileSize {
+		return nil, nil
+	}
+	content, err := io.ReadAll(io.LimitReader(f, c.config.MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > c.config.MaxFileSize {
+		// The file grew since it was opened; rewind so it can be served directly
+		// 파일이 열린 이후 커졌으므로 직접 제공할 수 있도록 되감음
+		_, err := f.Seek(0, io.SeekStart)
+		return nil, err
+	}
+
+	e := &cacheEntry{name: name, content: content, modTime: stat.ModTime(), size: stat.Size()}
+	e.contentType = mime.TypeByExtension(path.Ext(name))
+	if e.contentType == "" {
+		e.contentType = http.DetectContentType(content)
+	}
+	if h.cfg.etag == ETagContentHash {
+		sum := sha512.Sum384(content)
+		e.etag = contentHashETag(sum[:])
+	} else if e.etag, err = h.etag(name, f, stat); err != nil {
+		return nil, err
+	}
+
+	for _, enc := range precompressedEncodings {
+		if v, ok := h.readSibling(name+enc.ext, c.config.MaxFileSize); ok {
+			if e.variants == nil {
+				e.variants = make(map[string][]byte)
+			}
+			e.variants[enc.coding] = v
+		}
+	}
+	if _, ok := e.variants["gzip"]; !ok && isCompressible(e.contentType) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(content)
+		zw.Close()
+		if buf.Len() < len(content) {
+			if e.variants == nil {
+				e.variants = make(map[string][]byte)
+			}
+			e.variants["gzip"] = buf.Bytes()
+		}
+	}
+
+	c.add(e)
+	return e, nil
+}
+
+// readSibling reads a precompressed sibling file, reporting false if it is missing, denied or too large.
+// readSibling은 미리 압축된 형제 파일을 읽으며, 없거나 거부되었거나 너무 크면 false를 반환합니다.
+func (h *handler) readSibling(name string, limit int64) ([]byte, bool) {
+	if h.cfg.isDenied(name) {
+		return nil, false
+	}
+	f, stat, err := h.open(name)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+	if stat.Size() > limit {
+		return nil, false
+	}
+	b, err := io.ReadAll(f)
+	return b, err == nil
+}
+
+// serveEntry serves a cached file, choosing a compressed variant if the client accepts it.
+// serveEntry는 캐시된 파일을 제공하며, 클라이언트가 허용하면 압축된 변형을 선택합니다.
+func (h *handler) serveEntry(w http.ResponseWriter, r *http.Request, e *cacheEntry, policy CachePolicy) {
+	policy.apply(w.Header())
+	h.cfg.applyHeaders(w.Header())
+
+	body, etag := e.content, e.etag
+	if len(e.variants) > 0 {
+		w.Header().Add("Vary", "Accept-Encoding")
+		for _, enc := range precompressedEncodings {
+			if v, ok := e.variants[enc.coding]; ok && acceptsEncoding(r, enc.coding) {
+				w.Header().Set("Content-Encoding", enc.coding)
+				body = v
+				if etag != "" {
+					// Each representation needs its own strong validator
+					// 각 표현마다 고유한 강한 검증자가 필요함
+					etag = strings.TrimSuffix(etag, `"`) + "-" + enc.coding + `"`
+				}
+				break
+			}
+		}
+	}
+	w.Header().Set("Content-Type", e.contentType)
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	modTime := e.modTime
+	if !h.cfg.modTime.IsZero() {
+		modTime = h.cfg.modTime
+	}
+
+	h.serveBody(w, r, e.name, path.Base(e.name), modTime, bytes.NewReader(body), int64(len(body)))
+}
+
+// stat returns the file info of the named file, for revalidating cache entries.
+// stat은 캐시 항목 재검증을 위해 지정된 파일의 정보를 반환합니다.
+func (h *handler) stat(name string) (os.FileInfo, error) {
+	f, stat, err := h.open(name)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return stat, nil
+}
+
+// acceptsEncoding reports whether the request's Accept-Encoding allows coding. An entry naming coding
+// overrides "*", whatever their order.
+// acceptsEncoding은 요청의 Accept-Encoding이 coding을 허용하는지 여부를 반환합니다. coding을 지정한 항목은
+// 순서와 관계없이 "*"보다 우선합니다.
+func acceptsEncoding(r *http.Request, coding string) bool {
+	exact, wildcard := -1, -1
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.TrimSpace(name)
+		q := strings.ReplaceAll(params, " ", "")
+		allowed := 0
+		if !strings.HasPrefix(q, "q=0") || strings.HasPrefix(q, "q=0.") && strings.Trim(q[4:], "0") != "" {
+			allowed = 1
+		}
+		switch {
+		case strings.EqualFold(name, coding):
+			exact = allowed
+		case name == "*":
+			wildcard = allowed
+		}
+	}
+	if exact >= 0 {
+		return exact == 1
+	}
+	return wildcard == 1
+}
+
+// isCompressible reports whether contentType is listed in compress.DefaultCompressibleContentTypes.
+// isCompressible은 contentType이 compress.DefaultCompressibleContentTypes에 포함되는지 여부를 반환합니다.
+func isCompressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	for _, t := range compress.DefaultCompressibleContentTypes {
+		if strings.EqualFold(mediaType, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,410 @@
+package fileserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// config holds the optional settings of the file server.
+// config는 파일 서버의 선택적 설정을 보관합니다.
+type config struct {
+	// prefix is prepended to every file path inside the file system.
+	// prefix는 파일 시스템 내부의 모든 파일 경로 앞에 추가됩니다.
+	prefix string
+	// cachePolicy is the cache policy of files matching no cache rule.
+	// cachePolicy는 어떤 캐시 규칙에도 일치하지 않는 파일의 캐시 정책입니다.
+	cachePolicy CachePolicy
+	// crossOriginResourcePolicy is sent as Cross-Origin-Resource-Policy on served files.
+	// crossOriginResourcePolicy는 제공되는 파일에 Cross-Origin-Resource-Policy로 전송됩니다.
+	crossOriginResourcePolicy string
+	// fingerprints resolves content-hashed names to logical names.
+	// fingerprints는 콘텐츠 해시가 포함된 이름을 논리적 이름으로 변환합니다.
+	fingerprints *Fingerprinter
+	// integrity computes SRI strings through the file server.
+	// integrity는 파일 서버를 통해 SRI 문자열을 계산합니다.
+	integrity *Integrity
+	// cacheRules are evaluated in order to pick the cache policy of a file.
+	// cacheRules는 파일의 캐시 정책을 고르기 위해 순서대로 평가됩니다.
+	cacheRules []CacheRule
+	// spaEntry is the file served for unknown routes in SPA mode, or "" if SPA mode is disabled.
+	// spaEntry는 SPA 모드에서 알 수 없는 경로에 제공되는 파일이며, SPA 모드가 비활성화되면 ""입니다.
+	spaEntry string
+	// indexFiles are the file names served for directory requests, in order of preference.
+	// indexFiles는 디렉토리 요청에 제공되는 파일 이름 목록으로, 우선순위 순서입니다.
+	indexFiles []string
+	// listing renders directories without an index file, or is nil if listing is disabled.
+	// listing은 인덱스 파일이 없는 디렉토리를 렌더링하며, 리스팅이 비활성화되면 nil입니다.
+	listing *Listing
+	// dotfiles serves files and directories whose name starts with ".", which are denied by default.
+	// dotfiles는 기본적으로 거부되는, 이름이 "."으로 시작하는 파일과 디렉토리를 제공합니다.
+	dotfiles bool
+	// denyPatterns and allowPatterns are glob patterns of paths that are denied or always allowed.
+	// denyPatterns와 allowPatterns는 거부되거나 항상 허용되는 경로의 glob 패턴입니다.
+	denyPatterns  []string
+	allowPatterns []string
+	// symlinks is the symlink policy.
+	// symlinks는 심볼릭 링크 정책입니다.
+	symlinks SymlinkPolicy
+	// etag is the ETag strategy.
+	// etag는 ETag 전략입니다.
+	etag ETagStrategy
+	// modTime, if not zero, replaces the modification time of every served file.
+	// modTime이 0이 아니면 제공되는 모든 파일의 수정 시간을 대체합니다.
+	modTime time.Time
+	// memoryCache caches small files in memory, or is nil if caching is disabled.
+	// memoryCache는 작은 파일을 메모리에 캐시하며, 캐싱이 비활성화되면 nil입니다.
+	memoryCache *MemoryCache
+	// errorPages maps status codes to custom error responses.
+	// errorPages는 상태 코드를 사용자 정의 오류 응답에 매핑합니다.
+	errorPages map[int]errorPage
+	// ranges limits the byte ranges clients may request, or is nil if range requests are not limited.
+	// ranges는 클라이언트가 요청할 수 있는 바이트 범위를 제한하며, 범위 요청을 제한하지 않으면 nil입니다.
+	ranges *Ranges
+	// throttle limits the bandwidth and concurrency of large transfers, or is nil if they are not limited.
+	// throttle은 대용량 전송의 대역폭과 동시성을 제한하며, 제한하지 않으면 nil입니다.
+	throttle *Throttle
+	// signer verifies signed URLs, or is nil if files are public.
+	// signer는 서명된 URL을 검증하며, 파일이 공개되어 있으면 nil입니다.
+	signer *URLSigner
+}
+
+// Option configures optional behavior of the file server.
+// Option은 파일 서버의 선택적 동작을 설정합니다.
+type Option func(*config)
+
+// WithPrefix prepends prefix to every file path looked up in the file system, so "/app.js" is served from "<prefix>/app.js".
+//
+// WithPrefix는 파일 시스템에서 찾는 모든 파일 경로 앞에 prefix를 추가하므로, "/app.js"는 "<prefix>/app.js"에서 제공됩니다.
+func WithPrefix(prefix string) Option {
+	return func(c *config) {
+		c.prefix = prefix
+	}
+}
+
+// WithCachePolicy sets the cache policy of files that match no cache rule. By default no caching headers are sent.
+//
+// WithCachePolicy는 어떤 캐시 규칙에도 일치하지 않는 파일의 캐시 정책을 설정합니다. 기본적으로 캐시 헤더는 전송되지 않습니다.
+func WithCachePolicy(policy CachePolicy) Option {
+	return func(c *config) {
+		c.cachePolicy = policy
+	}
+}
+
+// WithCrossOriginResourcePolicy sets the Cross-Origin-Resource-Policy header on every served file.
+// Cross-origin isolated documents (see secure.CrossOriginIsolation) can only load assets carrying this header.
+// Use one of secure.ResourcePolicySameOrigin, secure.ResourcePolicySameSite or secure.ResourcePolicyCrossOrigin.
+//
+// WithCrossOriginResourcePolicy는 제공되는 모든 파일에 Cross-Origin-Resource-Policy 헤더를 설정합니다.
+// 교차 출처 격리된 문서(secure.CrossOriginIsolation 참고)는 이 헤더가 있는 에셋만 불러올 수 있습니다.
+// secure.ResourcePolicySameOrigin, secure.ResourcePolicySameSite, secure.ResourcePolicyCrossOrigin 중 하나를 사용하세요.
+func WithCrossOriginResourcePolicy(policy string) Option {
+	return func(c *config) {
+		c.crossOriginResourcePolicy = policy
+	}
+}
+
+// WithFingerprints serves the content-hashed URLs produced by fp. Fingerprinted URLs are served
+// with "public, max-age=31536000, immutable", while plain names are served with "no-cache" so they are revalidated,
+// unless a cache rule matches them.
+// URLs carrying a stale fingerprint are answered with 404 Not Found.
+//
+// WithFingerprints는 fp가 생성한 콘텐츠 해시 URL을 제공합니다. 지문이 포함된 URL은
+// "public, max-age=31536000, immutable"로, 일반 이름은 캐시 규칙에 일치하지 않는 한 재검증되도록 "no-cache"로 제공됩니다.
+// 오래된 지문을 가진 URL은 404 Not Found로 응답합니다.
+func WithFingerprints(fp *Fingerprinter) Option {
+	return func(c *config) {
+		c.fingerprints = fp
+	}
+}
+
+// WithIntegrity makes i resolve and hash names through the file server, sharing its digest cache with
+// content-hash ETags and fingerprints.
+//
+// WithIntegrity는 i가 파일 서버를 통해 이름을 확인하고 해시하도록 하며, 다이제스트 캐시를 콘텐츠 해시 ETag 및
+// 지문과 공유합니다.
+func WithIntegrity(i *Integrity) Option {
+	return func(c *config) {
+		c.integrity = i
+	}
+}
+
+// WithCacheRules sets cache policies per path or type. Rules are evaluated in order and the first match wins;
+// files matching no rule use the policy set by WithCachePolicy (the cacheMaxAgeSeconds policy of Run). For example:
+//
+//	fileserver.WithCacheRules(
+//		fileserver.CacheRule{Match: fileserver.MatchGlob("*.html"), Policy: fileserver.CachePolicy{NoCache: true}},
+//		fileserver.CacheRule{Match: fileserver.MatchGlob("/assets/"), Policy: fileserver.CachePolicy{Public: true, MaxAge: 365 * 24 * time.Hour, Immutable: true}},
+//		fileserver.CacheRule{Match: fileserver.MatchContentType("image/*"), Policy: fileserver.CachePolicy{Public: true, MaxAge: 7 * 24 * time.Hour}},
+//	)
+//
+// WithCacheRules는 경로나 타입별 캐시 정책을 설정합니다. 규칙은 순서대로 평가되며 처음 일치하는 규칙이 적용됩니다.
+// 어떤 규칙에도 일치하지 않는 파일은 WithCachePolicy로 설정한 정책(Run의 cacheMaxAgeSeconds 정책)을 사용합니다.
+func WithCacheRules(rules ...CacheRule) Option {
+	return func(c *config) {
+		c.cacheRules = append(c.cacheRules, rules...)
+	}
+}
+
+// WithIndexFiles sets the file names served for directory requests, in order of preference.
+// Defaults to "index.html". Directories without any of these files are answered with 403 Forbidden;
+// calling WithIndexFiles without names disables index files entirely.
+//
+// WithIndexFiles는 디렉토리 요청에 제공되는 파일 이름을 우선순위 순서로 설정합니다.
+// 기본값은 "index.html"입니다. 이 파일들이 하나도 없는 디렉토리는 403 Forbidden으로 응답하며,
+// 이름 없이 WithIndexFiles를 호출하면 인덱스 파일을 완전히 비활성화합니다.
+func WithIndexFiles(names ...string) Option {
+	return func(c *config) {
+		c.indexFiles = append([]string{}, names...)
+	}
+}
+
+// WithListing enables directory listings for directories without an index file, e.g. for internal artifact shares.
+// Listings are rendered as HTML, or as JSON (see ListingPage) if the client accepts application/json,
+// and can be sorted with the query parameters "sort" (name, size, mtime) and "order" (asc, desc).
+// The built-in template marks its inline styles with the nonce of secure.NonceHeaders, so it works under a strict CSP.
+//
+// WithListing은 인덱스 파일이 없는 디렉토리에 대한 디렉토리 리스팅을 활성화합니다(예: 내부 산출물 공유).
+// 리스팅은 HTML로 렌더링되며, 클라이언트가 application/json을 허용하면 JSON(ListingPage 참고)으로 렌더링됩니다.
+// 쿼리 파라미터 "sort"(name, size, mtime)와 "order"(asc, desc)로 정렬할 수 있습니다.
+// 내장 템플릿은 인라인 스타일에 secure.NonceHeaders의 nonce를 표시하므로 엄격한 CSP에서도 동작합니다.
+func WithListing(listing Listing) Option {
+	return func(c *config) {
+		c.listing = &listing
+	}
+}
+
+// WithDotfiles serves files and directories whose name starts with ".", such as ".env" or ".git/config".
+// They are answered with 404 Not Found by default. Prefer WithAllow for single paths such as "/.well-known/".
+//
+// WithDotfiles는 ".env"나 ".git/config"처럼 이름이 "."으로 시작하는 파일과 디렉토리를 제공합니다.
+// 기본적으로 이들은 404 Not Found로 응답합니다. "/.well-known/"과 같은 개별 경로에는 WithAllow를 사용하세요.
+func WithDotfiles(serve bool) Option {
+	return func(c *config) {
+		c.dotfiles = serve
+	}
+}
+
+// WithDeny denies paths matching any of the glob patterns, with the pattern syntax of MatchGlob,
+// e.g. WithDeny("*~", "*.bak", "/internal/"). Patterns are matched against the cleaned request path
+// before the file system is accessed, and denied paths are answered with 404 Not Found so their existence is not leaked.
+//
+// WithDeny는 MatchGlob의 패턴 문법으로 glob 패턴 중 하나와 일치하는 경로를 거부합니다
+// (예: WithDeny("*~", "*.bak", "/internal/")). 패턴은 파일 시스템에 접근하기 전에 정리된 요청 경로와 비교되며,
+// 거부된 경로는 존재 여부가 노출되지 않도록 404 Not Found로 응답합니다.
+func WithDeny(patterns ...string) Option {
+	return func(c *config) {
+		c.denyPatterns = append(c.denyPatterns, patterns...)
+	}
+}
+
+// WithAllow always serves paths matching any of the glob patterns, overriding WithDeny, e.g. WithAllow("/.well-known/").
+// A pattern overrides the dotfile rule only for the dot segments it spells out, so "/.well-known/" does not expose
+// "/.well-known/.git/" and "*.txt" does not expose "/.git/notes.txt".
+//
+// WithAllow는 glob 패턴 중 하나와 일치하는 경로를 WithDeny보다 우선하여 항상 제공합니다(예: WithAllow("/.well-known/")).
+// 패턴은 명시한 점 세그먼트에 대해서만 dotfile 규칙보다 우선하므로, "/.well-known/"은 "/.well-known/.git/"을,
+// "*.txt"는 "/.git/notes.txt"를 노출하지 않습니다.
+func WithAllow(patterns ...string) Option {
+	return func(c *config) {
+		c.allowPatterns = append(c.allowPatterns, patterns...)
+	}
+}
+
+// WithSymlinks sets how symbolic links below the served root are treated. By default only links that stay
+// inside the root are followed (SymlinksWithinRoot). The policy is enforced with os.Root on the http.Dir fast path,
+// and on other file systems if they report symbolic links by implementing the Lstat and ReadLink methods of fs.ReadLinkFS.
+//
+// WithSymlinks는 제공 루트 아래의 심볼릭 링크를 다루는 방식을 설정합니다. 기본적으로 루트 안에 머무는 링크만
+// 따라갑니다(SymlinksWithinRoot). 정책은 http.Dir 빠른 경로에서는 os.Root로 적용되며, 다른 파일 시스템에서는
+// fs.ReadLinkFS의 Lstat과 ReadLink 메서드를 구현하여 심볼릭 링크를 보고하는 경우에 적용됩니다.
+func WithSymlinks(policy SymlinkPolicy) Option {
+	return func(c *config) {
+		c.symlinks = policy
+	}
+}
+
+// WithETag sets the ETag strategy of served files. New sends no ETags by default and NewFS uses ETagContentHash.
+// Conditional requests (If-None-Match, If-Match and If-Range) are evaluated against the ETag by http.ServeContent.
+//
+// WithETag는 제공되는 파일의 ETag 전략을 설정합니다. New는 기본적으로 ETag를 전송하지 않으며 NewFS는 ETagContentHash를 사용합니다.
+// 조건부 요청(If-None-Match, If-Match, If-Range)은 http.ServeContent가 ETag를 기준으로 평가합니다.
+func WithETag(strategy ETagStrategy) Option {
+	return func(c *config) {
+		c.etag = strategy
+	}
+}
+
+// WithModTime sends t as the Last-Modified time of every served file, e.g. the build time of a binary embedding
+// its assets with embed.FS, which reports zero modification times. Set it from a build-time variable such as
+// -ldflags "-X main.buildTime=..." so it only changes with new deployments.
+//
+// WithModTime은 t를 제공되는 모든 파일의 Last-Modified 시간으로 전송합니다. 예를 들어 수정 시간을 0으로 보고하는
+// embed.FS로 에셋을 포함한 바이너리의 빌드 시간을 사용합니다. 새 배포 때만 바뀌도록
+// -ldflags "-X main.buildTime=..."와 같은 빌드 시점 변수로 설정하세요.
+func WithModTime(t time.Time) Option {
+	return func(c *config) {
+		c.modTime = t
+	}
+}
+
+// WithMemoryCache serves small files from c instead of opening them on every request. Compressed variants are
+// served to clients accepting them, with "Vary: Accept-Encoding".
+//
+//	cache := fileserver.NewMemoryCache(fileserver.MemoryCacheConfig{MaxSize: 64 << 20})
+//	h, err := fileserver.New(http.Dir("./public"), fileserver.WithMemoryCache(cache))
+//
+// WithMemoryCache는 매 요청마다 파일을 여는 대신 c에서 작은 파일을 제공합니다. 압축된 변형은
+// 이를 허용하는 클라이언트에게 "Vary: Accept-Encoding"과 함께 제공됩니다.
+func WithMemoryCache(c *MemoryCache) Option {
+	return func(cfg *config) {
+		cfg.memoryCache = c
+	}
+}
+
+// WithErrorPage answers errors with status (e.g. 404 or 403) with the named file of the served file system,
+// such as "/404.html". The page is sent with the error status, a content type derived from its name and
+// "Cache-Control: no-store". If the file cannot be read, the default httperror response is used.
+//
+// WithErrorPage는 status(예: 404, 403) 오류에 제공되는 파일 시스템의 지정된 파일(예: "/404.html")로 응답합니다.
+// 페이지는 오류 상태, 이름에서 얻은 콘텐츠 타입, "Cache-Control: no-store"와 함께 전송됩니다.
+// 파일을 읽을 수 없으면 기본 httperror 응답이 사용됩니다.
+func WithErrorPage(status int, name string) Option {
+	return func(c *config) {
+		c.setErrorPage(status, errorPage{name: path.Clean("/" + name)})
+	}
+}
+
+// WithErrorHandler answers errors with status using handler. Whatever status the handler writes,
+// the response is sent with status and "Cache-Control: no-store".
+//
+// WithErrorHandler는 status 오류에 handler로 응답합니다. 핸들러가 어떤 상태를 쓰든
+// 응답은 status와 "Cache-Control: no-store"로 전송됩니다.
+func WithErrorHandler(status int, handler http.Handler) Option {
+	return func(c *config) {
+		c.setErrorPage(status, errorPage{handler: handler})
+	}
+}
+
+// setErrorPage registers the error page of status.
+// setErrorPage는 status의 오류 페이지를 등록합니다.
+func (c *config) setErrorPage(status int, page errorPage) {
+	if c.errorPages == nil {
+		c.errorPages = make(map[int]errorPage)
+	}
+	c.errorPages[status] = page
+}
+
+// WithSPA enables single-page application mode. Requests for missing files are answered with the entry file
+// (e.g. "/index.html") if the path has no extension or the request accepts text/html, so client-side routes
+// such as "/users/42" load the application while missing assets such as "/missing.js" still return 404.
+// The entry file is served with "no-cache" so new deployments are picked up.
+//
+// WithSPA는 단일 페이지 애플리케이션 모드를 활성화합니다. 존재하지 않는 파일에 대한 요청은 경로에 확장자가 없거나
+// 요청이 text/html을 허용하면 진입 파일(예: "/index.html")로 응답하므로, "/users/42"와 같은 클라이언트 측 경로는
+// 애플리케이션을 불러오고 "/missing.js"와 같이 존재하지 않는 에셋은 여전히 404를 반환합니다.
+// 진입 파일은 새 배포가 반영되도록 "no-cache"로 제공됩니다.
+func WithSPA(entry string) Option {
+	return func(c *config) {
+		c.spaEntry = path.Clean("/" + entry)
+	}
+}
+
+// WithRanges limits the byte ranges clients may request, protecting large downloads from requests for
+// many small or overlapping ranges. See Ranges.
+//
+// WithRanges는 클라이언트가 요청할 수 있는 바이트 범위를 제한하여, 작거나 겹치는 범위를 많이 요청하는 것으로부터
+// 대용량 다운로드를 보호합니다. Ranges를 참고하세요.
+func WithRanges(ranges Ranges) Option {
+	return func(c *config) {
+		c.ranges = &ranges
+	}
+}
+
+// WithThrottle limits the bandwidth of large transfers per connection and per client, and the number of
+// large transfers in progress. See Throttle.
+//
+// WithThrottle은 연결별, 클라이언트별 대용량 전송 대역폭과 진행 중인 대용량 전송의 수를 제한합니다.
+// Throttle을 참고하세요.
+func WithThrottle(throttle Throttle) Option {
+	return func(c *config) {
+		c.throttle = &throttle
+	}
+}
+
+// WithSignedURLs serves files only for URLs signed by signer that have not expired; other requests are
+// answered with 403. Signed URLs are usually private, so consider a cache policy that keeps shared caches
+// from storing them.
+//
+// WithSignedURLs는 signer가 서명하고 만료되지 않은 URL에 대해서만 파일을 제공하며, 그 외 요청에는 403으로 응답합니다.
+// 서명된 URL은 보통 비공개이므로 공유 캐시가 저장하지 않도록 하는 캐시 정책을 고려하세요.
+func WithSignedURLs(signer *URLSigner) Option {
+	return func(c *config) {
+		c.signer = signer
+	}
+}
+
+// validate checks the applied options for invalid values.
+// validate는 적용된 옵션에 유효하지 않은 값이 있는지 확인합니다.
+func (c *config) validate() error {
+	switch c.crossOriginResourcePolicy {
+	case "", "same-origin", "same-site", "cross-origin":
+	default:
+		return fmt.Errorf("fileserver: invalid Cross-Origin-Resource-Policy %q", c.crossOriginResourcePolicy)
+	}
+	for _, name := range c.indexFiles {
+		if name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("fileserver: invalid index file name %q", name)
+		}
+	}
+	if c.symlinks < SymlinksWithinRoot || c.symlinks > SymlinksDeny {
+		return fmt.Errorf("fileserver: invalid symlink policy %d", c.symlinks)
+	}
+	if c.etag < ETagNone || c.etag > ETagInode {
+		return fmt.Errorf("fileserver: invalid ETag strategy %d", c.etag)
+	}
+	for status, page := range c.errorPages {
+		if status < 400 || status > 599 {
+			return fmt.Errorf("fileserver: invalid error page status %d", status)
+		}
+		if page.handler == nil && (page.name == "" || page.name == "/") {
+			return fmt.Errorf("fileserver: missing error page for status %d", status)
+		}
+	}
+	if c.ranges != nil {
+		if c.ranges.MaxRanges < 0 || c.ranges.MinRangeSize < 0 {
+			return fmt.Errorf("fileserver: invalid range limits: MaxRanges %d, MinRangeSize %d", c.ranges.MaxRanges, c.ranges.MinRangeSize)
+		}
+		if err := validateGlobs(c.ranges.Disable); err != nil {
+			return err
+		}
+	}
+	if c.signer != nil && len(c.signer.SecretKey) == 0 {
+		return errors.New("fileserver: URL signer requires a secret key")
+	}
+	if t := c.throttle; t != nil && (t.ConnectionRate < 0 || t.ClientRate < 0 || t.MaxConcurrent < 0 || t.QueueTimeout < 0 || t.MinSize < 0) {
+		return errors.New("fileserver: throttle limits must not be negative")
+	}
+	for _, rule := range c.cacheRules {
+		if globs, ok := rule.Match.(globMatcher); ok {
+			if err := validateGlobs(globs); err != nil {
+				return err
+			}
+		}
+	}
+	if err := validateGlobs(c.denyPatterns); err != nil {
+		return err
+	}
+	return validateGlobs(c.allowPatterns)
+}
+
+// applyHeaders sets the configured per-file response headers.
+// applyHeaders는 설정된 파일별 응답 헤더를 설정합니다.
+func (c *config) applyHeaders(h http.Header) {
+	if c.crossOriginResourcePolicy != "" {
+		h.Set("Cross-Origin-Resource-Policy", c.crossOriginResourcePolicy)
+	}
+}
@@ -0,0 +1,136 @@
+package fileserver
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+)
+
+// overlayFileSystem searches an ordered list of file systems, see Overlay.
+// overlayFileSystem은 순서가 있는 파일 시스템 목록을 검색합니다. Overlay를 참고하세요.
+type overlayFileSystem []http.FileSystem
+
+// Overlay returns a file system that looks up every name in roots in order, so files in earlier roots override
+// files in later ones, e.g. Overlay(http.Dir("tenants/acme"), http.Dir("theme")). Directories are merged across
+// roots, so listings and index files see the union of all roots. Passed to New, every http.Dir root keeps the
+// fast path and is confined by the symlink policy on its own.
+//
+// Overlay는 모든 이름을 roots에서 순서대로 찾는 파일 시스템을 반환하므로, 앞선 루트의 파일이 뒤의 루트의 파일을
+// 덮어씁니다(예: Overlay(http.Dir("tenants/acme"), http.Dir("theme"))). 디렉토리는 루트 간에 병합되므로 리스팅과
+// 인덱스 파일은 모든 루트의 합집합을 봅니다. New에 전달하면 각 http.Dir 루트는 빠른 경로를 유지하며
+// 각자 심볼릭 링크 정책으로 제한됩니다.
+func Overlay(roots ...http.FileSystem) http.FileSystem {
+	return overlayFileSystem(append([]http.FileSystem(nil), roots...))
+}
+
+// Open opens the named file from the first root containing it.
+// Open은 지정된 파일을 포함하는 첫 루트에서 파일을 엽니다.
+func (o overlayFileSystem) Open(name string) (http.File, error) {
+	layers := make([]func(string) (http.File, error), len(o))
+	for i, root := range o {
+		layers[i] = root.Open
+	}
+	return openOverlay(name, layers)
+}
+
+// openOverlay opens name from the first layer containing it. A file in an upper layer hides everything below it;
+// a directory is merged with the directories of the same name in lower layers.
+// openOverlay는 name을 포함하는 첫 레이어에서 name을 엽니다. 상위 레이어의 파일은 그 아래의 모든 것을 가리며,
+// 디렉토리는 하위 레이어의 같은 이름의 디렉토리와 병합됩니다.
+func openOverlay(name string, layers []func(string) (http.File, error)) (http.File, error) {
+	var top http.File
+	var lower []http.File
+	for _, open := range layers {
+		f, err := open(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			if top == nil {
+				return nil, err
+			}
+			continue
+		}
+		stat, err := f.Stat()
+		if err != nil {
+			f.Close()
+			if top == nil {
+				return nil, err
+			}
+			continue
+		}
+		switch {
+		case top == nil && !stat.IsDir():
+			return f, nil
+		case top == nil:
+			top = f
+		case stat.IsDir():
+			lower = append(lower, f)
+		default:
+			f.Close()
+		}
+	}
+	if top == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if len(lower) == 0 {
+		return top, nil
+	}
+	return &overlayDir{File: top, lower: lower}, nil
+}
+
+// overlayDir is a directory merged from several layers. Entries of upper layers hide lower entries of the same name.
+// overlayDir는 여러 레이어에서 병합된 디렉토리입니다. 상위 레이어의 항목은 같은 이름의 하위 항목을 가립니다.
+type overlayDir struct {
+	http.File
+	lower   []http.File
+	entries []os.FileInfo
+	read    bool
+}
+
+// Readdir returns the merged entries, following the semantics of os.File.Readdir.
+// Readdir는 os.File.Readdir의 의미에 따라 병합된 항목을 반환합니다.
+func (d *overlayDir) Readdir(count int) ([]os.FileInfo, error) {
+	if !d.read {
+		d.read = true
+		seen := make(map[string]bool)
+		for _, f := range append([]http.File{d.File}, d.lower...) {
+			infos, err := f.Readdir(-1)
+			if err != nil {
+				return nil, err
+			}
+			for _, info := range infos {
+				if !seen[info.Name()] {
+					seen[info.Name()] = true
+					d.entries = append(d.entries, info)
+				}
+			}
+		}
+	}
+
+	if count <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n := min(count, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+// Close closes the directories of all layers.
+// Close는 모든 레이어의 디렉토리를 닫습니다.
+func (d *overlayDir) Close() error {
+	err := d.File.Close()
+	for _, f := range d.lower {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
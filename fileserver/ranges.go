@@ -0,0 +1,227 @@
+package fileserver
+
+import (
+	"cmp"
+	"io"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Ranges limits the byte ranges clients may request. Requests exceeding the limits are answered with the
+// complete file, as RFC 9110 allows a server to ignore Range. The zero value imposes no limits.
+//
+// Ranges는 클라이언트가 요청할 수 있는 바이트 범위를 제한합니다. 제한을 넘는 요청에는 RFC 9110이 서버가
+// Range를 무시하도록 허용하므로 파일 전체로 응답합니다. 제로 값은 아무 제한도 두지 않습니다.
+type Ranges struct {
+	// MaxRanges is the maximum number of ranges of a request, counted after coalescing. Zero means no limit.
+	// MaxRanges는 요청의 최대 범위 수이며, 병합 후에 셉니다. 0이면 제한이 없습니다.
+	MaxRanges int
+	// Coalesce merges overlapping and adjacent ranges, so e.g. "bytes=0-99,50-199" is served as "bytes=0-199".
+	// Coalesce는 겹치거나 인접한 범위를 병합하므로, 예를 들어 "bytes=0-99,50-199"는 "bytes=0-199"로 제공됩니다.
+	Coalesce bool
+	// MinRangeSize is the minimum length of each range of a multi-range request. Zero means no limit.
+	// Requests for a single range are not affected.
+	// MinRangeSize는 다중 범위 요청의 각 범위의 최소 길이입니다. 0이면 제한이 없습니다.
+	// 단일 범위 요청에는 영향을 주지 않습니다.
+	MinRangeSize int64
+	// DisableMultipart answers multi-range requests with a single range spanning all requested ranges
+	// instead of a multipart/byteranges response.
+	// DisableMultipart는 다중 범위 요청에 multipart/byteranges 응답 대신 요청된 모든 범위를 포괄하는
+	// 단일 범위로 응답합니다.
+	DisableMultipart bool
+	// Disable lists glob patterns, as in WithDeny, of paths that do not support range requests:
+	// Range is ignored and "Accept-Ranges: none" is advertised.
+	// Disable은 범위 요청을 지원하지 않는 경로의 glob 패턴 목록이며 WithDeny와 같은 형식입니다.
+	// Range는 무시되고 "Accept-Ranges: none"이 알려집니다.
+	Disable []string
+}
+
+// byteRange is a range of content, from start for length bytes.
+// byteRange는 start부터 length 바이트만큼의 콘텐츠 범위입니다.
+type byteRange struct {
+	start, length int64
+}
+
+// limitRanges applies the range limits to a request for the named content of the given size. It returns the
+// request with a normalized Range header and, for paths with ranges disabled, a writer advertising "Accept-Ranges: none".
+//
+// limitRanges는 주어진 크기의 지정된 콘텐츠에 대한 요청에 범위 제한을 적용합니다. 정규화된 Range 헤더를 가진
+// 요청을 반환하며, 범위가 비활성화된 경로에는 "Accept-Ranges: none"을 알리는 writer를 반환합니다.
+func (h *handler) limitRanges(w http.ResponseWriter, r *http.Request, name string, size int64) (http.ResponseWriter, *http.Request) {
+	limits := h.cfg.ranges
+	if limits == nil {
+		return w, r
+	}
+	if matchGlobs(limits.Disable, name) {
+		return &noRangesWriter{ResponseWriter: w}, withRange(r, "")
+	}
+	header := r.Header.Get("Range")
+	if header == "" {
+		return w, r
+	}
+	ranges, ok := parseRanges(header, size)
+	if !ok {
+		// Leave malformed and unsatisfiable ranges to http.ServeContent, which answers 416
+		// 잘못되었거나 충족할 수 없는 범위는 416으로 응답하는 http.ServeContent에 맡김
+		return w, r
+	}
+
+	if limits.Coalesce {
+		ranges = coalesceRanges(ranges)
+	}
+	if limits.MaxRanges > 0 && len(ranges) > limits.MaxRanges {
+		return w, withRange(r, "")
+	}
+	if len(ranges) > 1 && limits.MinRangeSize > 0 {
+		for _, ra := range ranges {
+			if ra.length < limits.MinRangeSize {
+				return w, withRange(r, "")
+			}
+		}
+	}
+	if len(ranges) > 1 && limits.DisableMultipart {
+		start, end := ranges[0].start, ranges[0].start+ranges[0].length
+		for _, ra := range ranges[1:] {
+			start, end = min(start, ra.start), max(end, ra.start+ra.length)
+		}
+		ranges = []byteRange{{start: start, length: end - start}}
+	}
+
+	specs := make([]string, len(ranges))
+	for i, ra := range ranges {
+		specs[i] = strconv.FormatInt(ra.start, 10) + "-" + strconv.FormatInt(ra.start+ra.length-1, 10)
+	}
+	return w, withRange(r, "bytes="+strings.Join(specs, ","))
+}
+
+// withRange returns a shallow copy of r with the Range header replaced, or removed if value is empty.
+// withRange는 Range 헤더를 교체한 r의 얕은 복사본을 반환하며, value가 비어 있으면 헤더를 제거합니다.
+func withRange(r *http.Request, value string) *http.Request {
+	if r.Header.Get("Range") == value {
+		return r
+	}
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.Header = r.Header.Clone()
+	if value == "" {
+		r2.Header.Del("Range")
+	} else {
+		r2.Header.Set("Range", value)
+	}
+	return r2
+}
+
+// parseRanges parses a Range header like http.ServeContent, skipping ranges beyond the content.
+// It reports false if the header is malformed or no range overlaps the content.
+//
+// parseRanges는 http.ServeContent와 같이 Range 헤더를 파싱하며, 콘텐츠를 벗어난 범위는 건너뜁니다.
+// 헤더가 잘못되었거나 콘텐츠와 겹치는 범위가 없으면 false를 반환합니다.
+func parseRanges(header string, size int64) ([]byteRange, bool) {
+	specs, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return nil, false
+	}
+	var ranges []byteRange
+	for spec := range strings.SplitSeq(specs, ",") {
+		spec = textproto.TrimString(spec)
+		if spec == "" {
+			continue
+		}
+		first, last, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, false
+		}
+		first, last = textproto.TrimString(first), textproto.TrimString(last)
+		if first == "" {
+			// A suffix range: the last bytes of the content
+			// 접미사 범위: 콘텐츠의 마지막 바이트
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil, false
+			}
+			n = min(n, size)
+			if n > 0 {
+				ranges = append(ranges, byteRange{start: size - n, length: n})
+			}
+			continue
+		}
+		start, err := strconv.ParseInt(first, 10, 64)
+		if err != nil || start < 0 {
+			return nil, false
+		}
+		end := size - 1
+		if last != "" {
+			end, err = strconv.ParseInt(last, 10, 64)
+			if err != nil || end < start {
+				return nil, false
+			}
+			end = min(end, size-1)
+		}
+		if start < size {
+			ranges = append(ranges, byteRange{start: start, length: end - start + 1})
+		}
+	}
+	return ranges, len(ranges) > 0
+}
+
+// coalesceRanges sorts ranges and merges overlapping and adjacent ones.
+// coalesceRanges는 범위를 정렬하고 겹치거나 인접한 범위를 병합합니다.
+func coalesceRanges(ranges []byteRange) []byteRange {
+	slices.SortFunc(ranges, func(a, b byteRange) int {
+		return cmp.Compare(a.start, b.start)
+	})
+	merged := ranges[:1]
+	for _, ra := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if ra.start <= last.start+last.length {
+			last.length = max(last.length, ra.start+ra.length-last.start)
+			continue
+		}
+		merged = append(merged, ra)
+	}
+	return merged
+}
+
+// noRangesWriter advertises "Accept-Ranges: none", overriding the header set by http.ServeContent.
+// noRangesWriter는 http.ServeContent가 설정한 헤더를 덮어써 "Accept-Ranges: none"을 알립니다.
+type noRangesWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader replaces the Accept-Ranges header before writing the status code.
+// WriteHeader는 상태 코드를 쓰기 전에 Accept-Ranges 헤더를 교체합니다.
+func (w *noRangesWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Header().Set("Accept-Ranges", "none")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the header first if needed.
+// Write는 필요하면 헤더를 먼저 씁니다.
+func (w *noRangesWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// ReadFrom writes the header first if needed and copies through the underlying writer, so sendfile remains available.
+// ReadFrom은 필요하면 헤더를 먼저 쓰고 내부 writer를 통해 복사하므로 sendfile을 계속 사용할 수 있습니다.
+func (w *noRangesWriter) ReadFrom(src io.Reader) (int64, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return io.Copy(w.ResponseWriter, src)
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+// Unwrap은 http.ResponseController를 위해 내부 http.ResponseWriter를 반환합니다.
+func (w *noRangesWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,145 @@
+package fileserver
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DevNewbie1826/webUtil/internal/hmacsign"
+	"github.com/DevNewbie1826/webUtil/secure"
+)
+
+// Query parameters of signed URLs.
+// 서명된 URL의 쿼리 파라미터입니다.
+const (
+	signedExpiresParam   = "expires"
+	signedPrefixParam    = "prefix"
+	signedIPParam        = "ip"
+	signedSignatureParam = "signature"
+)
+
+// URLSigner creates and verifies signed, expiring URLs for private files. A signed URL carries its expiry
+// and an HMAC-SHA256 signature in the query parameters "expires" and "signature"; verify them with WithSignedURLs.
+//
+// URLSigner는 비공개 파일을 위한 서명되고 만료되는 URL을 생성하고 검증합니다. 서명된 URL은 만료 시각과
+// HMAC-SHA256 서명을 쿼리 파라미터 "expires"와 "signature"에 담으며, WithSignedURLs로 검증합니다.
+type URLSigner struct {
+	// SecretKey is used for HMAC signing.
+	// SecretKey는 HMAC 서명에 사용할 비밀키입니다.
+	SecretKey []byte
+}
+
+// SignOptions restricts where a signed URL may be used.
+// SignOptions는 서명된 URL을 사용할 수 있는 곳을 제한합니다.
+type SignOptions struct {
+	// ClientIP binds the URL to a client IP address, compared with secure.ClientIP when verifying.
+	// Put secure.RealIP in front of the file server when running behind proxies.
+	// ClientIP는 URL을 클라이언트 IP 주소에 묶으며, 검증 시 secure.ClientIP와 비교합니다.
+	// 프록시 뒤에서 실행할 때는 파일 서버 앞에 secure.RealIP를 두세요.
+	ClientIP string
+	// Prefix, ending with "/", makes the signature valid for every path below it instead of only the signed path,
+	// e.g. "/reports/2026/". The signed path must be below Prefix.
+	// "/"로 끝나는 Prefix는 서명이 서명된 경로에만이 아니라 그 아래 모든 경로에 유효하게 합니다(예: "/reports/2026/").
+	// 서명된 경로는 Prefix 아래에 있어야 합니다.
+	Prefix string
+}
+
+// signedPayload returns the signed value: the path or prefix the URL is valid for, the expiry and the bound client IP.
+// signedPayload는 서명되는 값을 반환합니다. URL이 유효한 경로 또는 접두사, 만료 시각, 묶인 클라이언트 IP로 구성됩니다.
+func signedPayload(scope, expires, clientIP string) string {
+	return scope + "\n" + expires + "\n" + clientIP
+}
+
+// Sign returns rawURL with the query parameters that make it valid until expires. The URL path must be
+// the path clients request, including any prefix the file server is mounted under. Existing query
+// parameters are kept but not signed.
+//
+// Sign은 expires까지 유효하게 만드는 쿼리 파라미터를 추가한 rawURL을 반환합니다. URL 경로는 파일 서버가
+// 마운트된 접두사를 포함하여 클라이언트가 요청하는 경로여야 합니다. 기존 쿼리 파라미터는 유지되지만 서명되지 않습니다.
+func (s *URLSigner) Sign(rawURL string, expires time.Time, opts SignOptions) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("fileserver: invalid URL: %w", err)
+	}
+	name := path.Clean("/" + u.Path)
+
+	query := u.Query()
+	for _, param := range []string{signedExpiresParam, signedPrefixParam, signedIPParam, signedSignatureParam} {
+		query.Del(param)
+	}
+	scope := "path:" + name
+	if opts.Prefix != "" {
+		if opts.Prefix != "/" && (!strings.HasPrefix(opts.Prefix, "/") || path.Clean(opts.Prefix)+"/" != opts.Prefix) {
+			return "", fmt.Errorf("fileserver: invalid signing prefix %q", opts.Prefix)
+		}
+		if !strings.HasPrefix(name, opts.Prefix) {
+			return "", fmt.Errorf("fileserver: path %q is not below signing prefix %q", name, opts.Prefix)
+		}
+		scope = "prefix:" + opts.Prefix
+		query.Set(signedPrefixParam, opts.Prefix)
+	}
+	clientIP := opts.ClientIP
+	if clientIP != "" {
+		addr, err := netip.ParseAddr(clientIP)
+		if err != nil {
+			return "", fmt.Errorf("fileserver: invalid client IP %q", clientIP)
+		}
+		clientIP = addr.Unmap().String()
+		query.Set(signedIPParam, "1")
+	}
+
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	query.Set(signedExpiresParam, exp)
+	query.Set(signedSignatureParam, hmacsign.Sign(s.SecretKey, signedPayload(scope, exp, clientIP), base64.RawURLEncoding))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Verify checks the signature and expiry of a request for a signed URL.
+// Verify는 서명된 URL에 대한 요청의 서명과 만료 시각을 확인합니다.
+func (s *URLSigner) Verify(r *http.Request) error {
+	query := r.URL.Query()
+	signature := query.Get(signedSignatureParam)
+	exp := query.Get(signedExpiresParam)
+	if signature == "" || exp == "" {
+		return errors.New("fileserver: missing URL signature")
+	}
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return errors.New("fileserver: invalid URL expiry")
+	}
+
+	// Verify the path clients requested, before any prefix was stripped
+	// 접두사가 제거되기 전에 클라이언트가 요청한 경로를 검증
+	requested := r.URL.Path
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		requested = u.Path
+	}
+	name := path.Clean("/" + requested)
+	scope := "path:" + name
+	if prefix := query.Get(signedPrefixParam); prefix != "" {
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(prefix, "/") {
+			return errors.New("fileserver: path is not below the signed prefix")
+		}
+		scope = "prefix:" + prefix
+	}
+	clientIP := ""
+	if query.Get(signedIPParam) != "" {
+		clientIP = secure.ClientIP(r)
+	}
+
+	if !hmacsign.Verify(s.SecretKey, signedPayload(scope, exp, clientIP), signature, base64.RawURLEncoding) {
+		return errors.New("fileserver: invalid URL signature")
+	}
+	if time.Now().Unix() > expires {
+		return errors.New("fileserver: signed URL expired")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package fileserver
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// spaCachePolicy is the cache policy of the SPA entry file, which must be revalidated so new deployments are picked up.
+// spaCachePolicy는 새 배포가 반영되도록 재검증해야 하는 SPA 진입 파일의 캐시 정책입니다.
+var spaCachePolicy = CachePolicy{NoCache: true}
+
+// isSPARoute reports whether a request for a missing file should be answered with the SPA entry file.
+// This is the case for GET and HEAD requests to extensionless paths or requests accepting text/html,
+// so missing assets such as "/missing.js" still get a real 404.
+// isSPARoute는 존재하지 않는 파일에 대한 요청에 SPA 진입 파일로 응답해야 하는지 여부를 반환합니다.
+// 확장자가 없는 경로나 text/html을 허용하는 GET 및 HEAD 요청이 해당하며,
+// 따라서 "/missing.js"와 같이 존재하지 않는 에셋은 여전히 실제 404를 받습니다.
+func isSPARoute(r *http.Request, name string) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	return path.Ext(name) == "" || strings.Contains(r.Header.Get("Accept"), "text/html")
+}
@@ -0,0 +1,175 @@
+package fileserver
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxSymlinkHops is the maximum number of symbolic links followed while resolving a single path.
+// maxSymlinkHops는 하나의 경로를 해석하는 동안 따라가는 심볼릭 링크의 최대 개수입니다.
+const maxSymlinkHops = 40
+
+// SymlinkPolicy controls how the file server treats symbolic links below the served root.
+// SymlinkPolicy는 파일 서버가 제공 루트 아래의 심볼릭 링크를 다루는 방식을 제어합니다.
+type SymlinkPolicy int
+
+const (
+	// SymlinksWithinRoot follows symbolic links whose target stays inside the served root. This is the default.
+	// Links pointing outside the root, including links with absolute targets, are answered with 404 Not Found.
+	// SymlinksWithinRoot는 대상이 제공 루트 안에 머무는 심볼릭 링크만 따라갑니다. 기본값입니다.
+	// 절대 경로 대상을 가진 링크를 포함하여 루트 밖을 가리키는 링크는 404 Not Found로 응답합니다.
+	SymlinksWithinRoot SymlinkPolicy = iota
+	// SymlinksFollow follows every symbolic link, even outside the served root.
+	// SymlinksFollow는 제공 루트 밖이더라도 모든 심볼릭 링크를 따라갑니다.
+	SymlinksFollow
+	// SymlinksDeny answers every path containing a symbolic link with 404 Not Found.
+	// SymlinksDeny는 심볼릭 링크를 포함하는 모든 경로를 404 Not Found로 응답합니다.
+	SymlinksDeny
+)
+
+// linkFileSystem is implemented by file systems that report symbolic links, with the method set of fs.ReadLinkFS.
+// Names are slash-separated and rooted like the names passed to http.FileSystem.Open.
+// linkFileSystem은 심볼릭 링크를 보고하는 파일 시스템이 구현하며, fs.ReadLinkFS의 메서드 집합을 가집니다.
+// 이름은 http.FileSystem.Open에 전달되는 이름처럼 슬래시로 구분되고 루트로 시작합니다.
+type linkFileSystem interface {
+	Lstat(name string) (os.FileInfo, error)
+	ReadLink(name string) (string, error)
+}
+
+// rootLinks adapts an os.Root to linkFileSystem.
+// rootLinks는 os.Root를 linkFileSystem에 맞게 변환합니다.
+type rootLinks struct {
+	root *os.Root
+}
+
+// Lstat returns the file info of the named file without following a final symbolic link.
+// Lstat은 마지막 심볼릭 링크를 따라가지 않고 지정된 파일의 정보를 반환합니다.
+func (l rootLinks) Lstat(name string) (os.FileInfo, error) {
+	return l.root.Lstat(rootName(name))
+}
+
+// ReadLink returns the slash-separated target of the named symbolic link.
+// ReadLink는 지정된 심볼릭 링크의 슬래시로 구분된 대상을 반환합니다.
+func (l rootLinks) ReadLink(name string) (string, error) {
+	target, err := l.root.Readlink(rootName(name))
+	if err != nil {
+		return "", err
+	}
+	return slashTarget(target), nil
+}
+
+// dirLinks adapts a local directory to linkFileSystem. Unlike rootLinks, it does not confine lookups,
+// so it is only used to find link targets under SymlinksFollow.
+// dirLinks는 로컬 디렉토리를 linkFileSystem에 맞게 변환합니다. rootLinks와 달리 조회를 제한하지 않으므로
+// SymlinksFollow에서 링크 대상을 찾는 데에만 사용됩니다.
+type dirLinks struct {
+	dir string
+}
+
+// Lstat returns the file info of the named file without following a final symbolic link.
+// Lstat은 마지막 심볼릭 링크를 따라가지 않고 지정된 파일의 정보를 반환합니다.
+func (l dirLinks) Lstat(name string) (os.FileInfo, error) {
+	return os.Lstat(filepath.Join(l.dir, rootName(name)))
+}
+
+// ReadLink returns the slash-separated target of the named symbolic link.
+// ReadLink는 지정된 심볼릭 링크의 슬래시로 구분된 대상을 반환합니다.
+func (l dirLinks) ReadLink(name string) (string, error) {
+	target, err := os.Readlink(filepath.Join(l.dir, rootName(name)))
+	if err != nil {
+		return "", err
+	}
+	return slashTarget(target), nil
+}
+
+// slashTarget converts a link target to slash-separated form.
+// slashTarget은 링크 대상을 슬래시 구분 형식으로 변환합니다.
+func slashTarget(target string) string {
+	if filepath.IsAbs(target) {
+		// Keep absolute targets recognizable on every platform
+		// 모든 플랫폼에서 절대 경로 대상을 인식할 수 있도록 유지
+		return "/" + filepath.ToSlash(target)
+	}
+	return filepath.ToSlash(target)
+}
+
+// prefixLinks applies the prefix of WithPrefix to a linkFileSystem.
+// prefixLinks는 WithPrefix의 접두사를 linkFileSystem에 적용합니다.
+type prefixLinks struct {
+	prefix string
+	fs     linkFileSystem
+}
+
+// Lstat returns the file info of the named file below the prefix.
+// Lstat은 접두사 아래 지정된 파일의 정보를 반환합니다.
+func (l prefixLinks) Lstat(name string) (os.FileInfo, error) {
+	return l.fs.Lstat(path.Join(l.prefix, name))
+}
+
+// ReadLink returns the target of the named symbolic link below the prefix.
+// ReadLink는 접두사 아래 지정된 심볼릭 링크의 대상을 반환합니다.
+func (l prefixLinks) ReadLink(name string) (string, error) {
+	return l.fs.ReadLink(path.Join(l.prefix, name))
+}
+
+// resolveLinks resolves the symbolic links in the cleaned path name according to policy and returns
+// the link-free path. Links that are denied or escape the root are reported as os.ErrNotExist,
+// so their existence is not leaked.
+// resolveLinks는 정리된 경로 name의 심볼릭 링크를 policy에 따라 해석하고 링크가 없는 경로를 반환합니다.
+// 거부되거나 루트를 벗어나는 링크는 존재 여부가 노출되지 않도록 os.ErrNotExist로 보고됩니다.
+func resolveLinks(links linkFileSystem, name string, policy SymlinkPolicy) (string, error) {
+	resolved := "/"
+	rest := strings.Split(name, "/")
+	for hops := 0; len(rest) > 0; {
+		segment := rest[0]
+		rest = rest[1:]
+		switch segment {
+		case "", ".":
+			continue
+		case "..":
+			// Only link targets contain "..", the request path is already cleaned
+			// 요청 경로는 이미 정리되어 있으므로 ".."은 링크 대상에만 포함됨
+			if resolved == "/" {
+				return "", os.ErrNotExist
+			}
+			resolved = path.Dir(resolved)
+			continue
+		}
+
+		next := path.Join(resolved, segment)
+		info, err := links.Lstat(next)
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		hops++
+		if policy == SymlinksDeny || hops > maxSymlinkHops {
+			return "", os.ErrNotExist
+		}
+		target, err := links.ReadLink(next)
+		if err != nil {
+			return "", err
+		}
+		if path.IsAbs(target) {
+			return "", os.ErrNotExist
+		}
+		rest = append(strings.Split(target, "/"), rest...)
+	}
+	return resolved, nil
+}
+
+// rootName converts a rooted slash-separated name into a name relative to an os.Root.
+// rootName은 루트로 시작하는 슬래시 구분 이름을 os.Root 기준의 상대 이름으로 변환합니다.
+func rootName(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+	return filepath.FromSlash(name)
+}
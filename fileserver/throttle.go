@@ -0,0 +1,324 @@
+package fileserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DevNewbie1826/webUtil/secure"
+)
+
+// defaultThrottleMinSize is the default size from which files are throttled and counted as large transfers.
+// defaultThrottleMinSize는 파일이 제한되고 대용량 전송으로 집계되는 기본 크기입니다.
+const defaultThrottleMinSize = 1 << 20
+
+// Throttle limits the bandwidth and concurrency of large file transfers. Responses that are not throttled
+// keep using sendfile.
+//
+// Throttle은 대용량 파일 전송의 대역폭과 동시성을 제한합니다. 제한되지 않는 응답은 계속 sendfile을 사용합니다.
+type Throttle struct {
+	// ConnectionRate is the maximum rate in bytes per second of each connection. Zero means no limit.
+	// ConnectionRate는 각 연결의 초당 최대 바이트 수입니다. 0이면 제한이 없습니다.
+	ConnectionRate int64
+	// ClientRate is the maximum rate in bytes per second shared by all transfers to the same client. Zero means no limit.
+	// ClientRate는 같은 클라이언트로의 모든 전송이 공유하는 초당 최대 바이트 수입니다. 0이면 제한이 없습니다.
+	ClientRate int64
+	// ClientKey identifies the client of a request for ClientRate. Defaults to secure.ClientIP.
+	// ClientKey는 ClientRate를 위해 요청의 클라이언트를 식별합니다. 기본값은 secure.ClientIP입니다.
+	ClientKey func(r *http.Request) string
+	// MaxConcurrent is the maximum number of large transfers in progress. Zero means no limit.
+	// MaxConcurrent는 진행 중인 대용량 전송의 최대 개수입니다. 0이면 제한이 없습니다.
+	MaxConcurrent int
+	// QueueTimeout is how long a transfer waits for a free slot when MaxConcurrent is reached, before it is
+	// answered with 503 Service Unavailable. Zero answers 503 immediately.
+	// QueueTimeout은 MaxConcurrent에 도달했을 때 전송이 빈 자리를 기다리는 시간이며, 이후에는
+	// 503 Service Unavailable로 응답합니다. 0이면 즉시 503으로 응답합니다.
+	QueueTimeout time.Duration
+	// MinSize is the size from which files are throttled and counted as large transfers. Defaults to 1 MiB.
+	// MinSize는 파일이 제한되고 대용량 전송으로 집계되는 크기입니다. 기본값은 1 MiB입니다.
+	MinSize int64
+}
+
+// errTransferRejected is returned to http.ServeContent for the body of a transfer answered with 503.
+// errTransferRejected는 503으로 응답한 전송의 본문에 대해 http.ServeContent에 반환됩니다.
+var errTransferRejected = errors.New("fileserver: transfer rejected by throttle")
+
+// throttler keeps the state shared by the transfers of a handler.
+// throttler는 핸들러의 전송들이 공유하는 상태를 보관합니다.
+type throttler struct {
+	config Throttle
+	slots  chan struct{}
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// newThrottler creates the throttling state for config.
+// newThrottler는 config에 대한 제한 상태를 생성합니다.
+func newThrottler(config Throttle) *throttler {
+	if config.MinSize == 0 {
+		config.MinSize = defaultThrottleMinSize
+	}
+	if config.ClientKey == nil {
+		config.ClientKey = secure.ClientIP
+	}
+	t := &throttler{config: config, buckets: make(map[string]*bucket)}
+	if config.MaxConcurrent > 0 {
+		t.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+	return t
+}
+
+// start begins a transfer of size bytes. It returns the writer to send the body through and a function
+// ending the transfer, or ok false if no slot became free in time.
+//
+// start는 size 바이트의 전송을 시작합니다. 본문을 보낼 writer와 전송을 끝내는 함수를 반환하며,
+// 제시간에 빈 자리가 나지 않으면 ok로 false를 반환합니다.
+func (t *throttler) start(w http.ResponseWriter, r *http.Request, size int64) (_ http.ResponseWriter, done func(), ok bool) {
+	if size < t.config.MinSize || r.Method == http.MethodHead {
+		return w, func() {}, true
+	}
+	if t.slots != nil && !t.acquire(r.Context()) {
+		return w, nil, false
+	}
+
+	var keys []string
+	var buckets []*bucket
+	if t.config.ConnectionRate > 0 {
+		keys = append(keys, "conn:"+r.RemoteAddr)
+		buckets = append(buckets, t.bucket(keys[len(keys)-1], t.config.ConnectionRate))
+	}
+	if key := t.config.ClientKey(r); t.config.ClientRate > 0 && key != "" {
+		keys = append(keys, "client:"+key)
+		buckets = append(buckets, t.bucket(keys[len(keys)-1], t.config.ClientRate))
+	}
+	done = func() {
+		for _, key := range keys {
+			t.unref(key)
+		}
+		if t.slots != nil {
+			<-t.slots
+		}
+	}
+	if len(buckets) == 0 {
+		return w, done, true
+	}
+	return &throttledWriter{ResponseWriter: w, ctx: r.Context(), buckets: buckets}, done, true
+}
+
+// wrap returns a writer that starts the transfer when the response header is written, sized by the
+// Content-Length http.ServeContent sets, so 304 responses and small ranges of large files are not counted.
+// size is used when the length is not known, e.g. for precompressed content. reject is called instead of
+// writing the header if no slot became free in time.
+//
+// wrap은 응답 헤더를 쓸 때 전송을 시작하는 writer를 반환합니다. 크기는 http.ServeContent가 설정한
+// Content-Length로 정해지므로 304 응답과 대용량 파일의 작은 범위는 집계되지 않습니다. 미리 압축된 콘텐츠처럼
+// 길이를 알 수 없으면 size를 사용합니다. 제시간에 빈 자리가 나지 않으면 헤더를 쓰는 대신 reject를 호출합니다.
+func (t *throttler) wrap(w http.ResponseWriter, r *http.Request, size int64, reject func()) *throttleGate {
+	return &throttleGate{ResponseWriter: w, t: t, r: r, size: size, reject: reject}
+}
+
+// acquire waits up to QueueTimeout for a transfer slot.
+// acquire는 최대 QueueTimeout 동안 전송 자리를 기다립니다.
+func (t *throttler) acquire(ctx context.Context) bool {
+	select {
+	case t.slots <- struct{}{}:
+		return true
+	default:
+	}
+	if t.config.QueueTimeout <= 0 {
+		return false
+	}
+	timer := time.NewTimer(t.config.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case t.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// bucket returns the token bucket of key, creating it with rate if needed, and counts a reference to it.
+// bucket은 key의 토큰 버킷을 반환하며, 필요하면 rate로 생성하고 참조 수를 셉니다.
+func (t *throttler) bucket(key string, rate int64) *bucket {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b, ok := t.buckets[key]
+	if !ok {
+		b = &bucket{rate: float64(rate), tokens: float64(rate), last: time.Now()}
+		t.buckets[key] = b
+	}
+	b.refs++
+	return b
+}
+
+// unref releases a reference to the bucket of key, dropping the bucket when no transfer uses it.
+// unref는 key의 버킷에 대한 참조를 해제하며, 사용하는 전송이 없으면 버킷을 제거합니다.
+func (t *throttler) unref(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if b := t.buckets[key]; b != nil {
+		if b.refs--; b.refs == 0 {
+			delete(t.buckets, key)
+		}
+	}
+}
+
+// bucket is a token bucket of bytes, holding at most one second worth of rate.
+// bucket은 바이트 단위의 토큰 버킷이며, 최대 1초 분량의 rate를 보관합니다.
+type bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+	refs   int
+}
+
+// wait takes n bytes from the bucket, sleeping until they are available.
+// wait는 버킷에서 n 바이트를 가져오며, 사용 가능해질 때까지 대기합니다.
+func (b *bucket) wait(ctx context.Context, n int) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*b.rate, b.rate) - float64(n)
+	b.last = now
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttleGate defers the throttling decision of a response to its header. Unthrottled bodies keep using sendfile.
+// throttleGate는 응답의 제한 여부 결정을 헤더까지 미룹니다. 제한되지 않는 본문은 계속 sendfile을 사용합니다.
+type throttleGate struct {
+	http.ResponseWriter
+	t      *throttler
+	r      *http.Request
+	size   int64
+	reject func()
+
+	body     io.Writer
+	done     func()
+	rejected bool
+}
+
+// WriteHeader starts the transfer for successful responses, or rejects it if no slot is free.
+// WriteHeader는 성공 응답에 대해 전송을 시작하며, 빈 자리가 없으면 거부합니다.
+func (w *throttleGate) WriteHeader(code int) {
+	if w.body != nil || w.rejected {
+		return
+	}
+	body := io.Writer(w.ResponseWriter)
+	if code == http.StatusOK || code == http.StatusPartialContent {
+		size, err := strconv.ParseInt(w.Header().Get("Content-Length"), 10, 64)
+		if err != nil {
+			size = w.size
+		}
+		tw, done, ok := w.t.start(w.ResponseWriter, w.r, size)
+		if !ok {
+			w.rejected = true
+			w.reject()
+			return
+		}
+		body, w.done = tw, done
+	}
+	w.body = body
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes b to the transfer, or fails if it was rejected.
+// Write는 b를 전송에 쓰며, 거부된 전송이면 실패합니다.
+func (w *throttleGate) Write(b []byte) (int, error) {
+	if w.body == nil && !w.rejected {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.rejected {
+		return 0, errTransferRejected
+	}
+	return w.body.Write(b)
+}
+
+// ReadFrom copies src to the transfer, with sendfile if it is not throttled.
+// ReadFrom은 src를 전송에 복사하며, 제한되지 않으면 sendfile을 사용합니다.
+func (w *throttleGate) ReadFrom(src io.Reader) (int64, error) {
+	if w.body == nil && !w.rejected {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.rejected {
+		return 0, errTransferRejected
+	}
+	return io.Copy(w.body, src)
+}
+
+// finish ends the transfer, if one was started.
+// finish는 시작된 전송이 있으면 종료합니다.
+func (w *throttleGate) finish() {
+	if w.done != nil {
+		w.done()
+	}
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+// Unwrap은 http.ResponseController를 위해 내부 http.ResponseWriter를 반환합니다.
+func (w *throttleGate) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// throttledWriter paces the response body through token buckets. It does not implement io.ReaderFrom,
+// so the body is copied in chunks rather than with sendfile.
+//
+// throttledWriter는 토큰 버킷을 통해 응답 본문의 속도를 조절합니다. io.ReaderFrom을 구현하지 않으므로
+// 본문은 sendfile 대신 청크 단위로 복사됩니다.
+type throttledWriter struct {
+	http.ResponseWriter
+	ctx     context.Context
+	buckets []*bucket
+}
+
+// Write writes b in chunks of at most a tenth of the slowest rate, waiting for each bucket before every chunk.
+// Write는 b를 가장 느린 rate의 1/10 이하 크기의 청크로 쓰며, 각 청크 전에 모든 버킷을 기다립니다.
+func (w *throttledWriter) Write(b []byte) (int, error) {
+	chunk := 32 << 10
+	for _, bucket := range w.buckets {
+		chunk = min(chunk, max(int(bucket.rate/10), 512))
+	}
+
+	written := 0
+	for len(b) > 0 {
+		n := min(len(b), chunk)
+		for _, bucket := range w.buckets {
+			if err := bucket.wait(w.ctx, n); err != nil {
+				return written, err
+			}
+		}
+		n, err := w.ResponseWriter.Write(b[:n])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+// Unwrap은 http.ResponseController를 위해 내부 http.ResponseWriter를 반환합니다.
+func (w *throttledWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
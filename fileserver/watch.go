@@ -0,0 +1,119 @@
+package fileserver
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// defaultPollInterval is the scan interval of the polling watcher used where inotify is not available.
+// defaultPollInterval은 inotify를 사용할 수 없는 환경에서 쓰이는 폴링 감시자의 검사 간격입니다.
+const defaultPollInterval = time.Second
+
+// watcher reports changes below a directory by calling a changeFunc. It is backed by inotify on Linux
+// and by polling elsewhere.
+// watcher는 changeFunc를 호출하여 디렉토리 아래의 변경 사항을 알립니다. Linux에서는 inotify를,
+// 그 외 환경에서는 폴링을 사용합니다.
+type watcher interface {
+	Close() error
+}
+
+// changeFunc is called with the changed slash-rooted name, e.g. "/css/app.css". If tree is true, everything
+// below name changed as well, e.g. because a directory was removed or renamed; "/" means anything may have changed.
+// changeFunc는 변경된 이름(예: "/css/app.css")으로 호출됩니다. tree가 true이면 디렉토리가 삭제되거나 이름이
+// 바뀐 경우처럼 name 아래의 모든 것도 바뀐 것이며, "/"는 무엇이든 바뀌었을 수 있음을 뜻합니다.
+type changeFunc func(name string, tree bool)
+
+// pollWatcher detects changes by periodically comparing the modification times and sizes of all files.
+// pollWatcher는 모든 파일의 수정 시간과 크기를 주기적으로 비교하여 변경 사항을 감지합니다.
+type pollWatcher struct {
+	dir      string
+	interval time.Duration
+	onChange changeFunc
+	files    map[string]fileState
+	stop     chan struct{}
+	once     sync.Once
+}
+
+// fileState is the state of a file compared by pollWatcher.
+// fileState는 pollWatcher가 비교하는 파일의 상태입니다.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// newPollWatcher starts a pollWatcher for dir.
+// newPollWatcher는 dir에 대한 pollWatcher를 시작합니다.
+func newPollWatcher(dir string, interval time.Duration, onChange changeFunc) (*pollWatcher, error) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	w := &pollWatcher{dir: dir, interval: interval, onChange: onChange, stop: make(chan struct{})}
+	files, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+	w.files = files
+	go w.run()
+	return w, nil
+}
+
+// run scans the directory until the watcher is closed.
+// run은 감시자가 닫힐 때까지 디렉토리를 검사합니다.
+func (w *pollWatcher) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+		files, err := w.scan()
+		if err != nil {
+			continue
+		}
+		for name, state := range files {
+			if old, ok := w.files[name]; !ok || old != state {
+				w.onChange(name, false)
+			}
+		}
+		for name := range w.files {
+			if _, ok := files[name]; !ok {
+				w.onChange(name, false)
+			}
+		}
+		w.files = files
+	}
+}
+
+// scan returns the state of every file below the directory.
+// scan은 디렉토리 아래 모든 파일의 상태를 반환합니다.
+func (w *pollWatcher) scan() (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	err := filepath.WalkDir(w.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(w.dir, p)
+		if err != nil {
+			return nil
+		}
+		files[path.Join("/", filepath.ToSlash(rel))] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return files, err
+}
+
+// Close stops the watcher.
+// Close는 감시자를 중지합니다.
+func (w *pollWatcher) Close() error {
+	w.once.Do(func() { close(w.stop) })
+	return nil
+}
@@ -0,0 +1,143 @@
+//go:build linux
+
+package fileserver
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// inotifyMask selects the inotify events that indicate a changed file.
+// inotifyMask는 파일 변경을 나타내는 inotify 이벤트를 선택합니다.
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_ATTRIB | syscall.IN_CREATE |
+	syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+
+// inotifyWatcher watches a directory tree with inotify, adding watches for directories created later.
+// inotifyWatcher는 inotify로 디렉토리 트리를 감시하며, 나중에 생성된 디렉토리에도 감시를 추가합니다.
+type inotifyWatcher struct {
+	dir      string
+	fd       int
+	file     *os.File
+	onChange changeFunc
+	mu       sync.Mutex
+	dirs     map[int32]string
+}
+
+// newWatcher starts an inotify watcher for dir.
+// newWatcher는 dir에 대한 inotify 감시자를 시작합니다.
+func newWatcher(dir string, onChange changeFunc) (watcher, error) {
+	// A non-blocking descriptor lets os.File use the runtime poller, so Close unblocks the reading goroutine
+	// 논블로킹 디스크립터는 os.File이 런타임 폴러를 사용하게 하므로 Close가 읽기 고루틴의 블로킹을 해제함
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	w := &inotifyWatcher{
+		dir:      dir,
+		fd:       fd,
+		file:     os.NewFile(uintptr(fd), "inotify"),
+		onChange: onChange,
+		dirs:     make(map[int32]string),
+	}
+	if err := w.addTree("/"); err != nil {
+		w.file.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// addTree adds watches for the named directory and every directory below it.
+// addTree는 지정된 디렉토리와 그 아래 모든 디렉토리에 감시를 추가합니다.
+func (w *inotifyWatcher) addTree(name string) error {
+	root := filepath.Join(w.dir, filepath.FromSlash(name))
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p != root && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		wd, err := syscall.InotifyAddWatch(w.fd, p, inotifyMask)
+		if err != nil {
+			return os.NewSyscallError("inotify_add_watch", err)
+		}
+		rel, _ := filepath.Rel(w.dir, p)
+		w.mu.Lock()
+		w.dirs[int32(wd)] = path.Join("/", filepath.ToSlash(rel))
+		w.mu.Unlock()
+		return nil
+	})
+}
+
+// run reads inotify events until the watcher is closed.
+// run은 감시자가 닫힐 때까지 inotify 이벤트를 읽습니다.
+func (w *inotifyWatcher) run() {
+	var buf [64 * (syscall.SizeofInotifyEvent + syscall.NAME_MAX + 1)]byte
+	for {
+		n, err := w.file.Read(buf[:])
+		if err != nil {
+			return
+		}
+		w.handle(buf[:n])
+	}
+}
+
+// handle reports the changes of a buffer of inotify events.
+// handle은 inotify 이벤트 버퍼의 변경 사항을 알립니다.
+func (w *inotifyWatcher) handle(buf []byte) {
+	for offset := 0; offset+syscall.SizeofInotifyEvent <= len(buf); {
+		event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		nameBytes := buf[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(event.Len)]
+		offset += syscall.SizeofInotifyEvent + int(event.Len)
+
+		if event.Mask&syscall.IN_Q_OVERFLOW != 0 {
+			// Events were lost, so anything may have changed
+			// 이벤트가 유실되었으므로 무엇이든 바뀌었을 수 있음
+			w.onChange("/", true)
+			continue
+		}
+
+		w.mu.Lock()
+		dir, ok := w.dirs[event.Wd]
+		if event.Mask&syscall.IN_IGNORED != 0 {
+			delete(w.dirs, event.Wd)
+		}
+		w.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		if event.Mask&(syscall.IN_DELETE_SELF|syscall.IN_MOVE_SELF) != 0 {
+			w.onChange(dir, true)
+			continue
+		}
+		name := path.Join(dir, strings.TrimRight(string(nameBytes), "\x00"))
+		if event.Mask&syscall.IN_ISDIR == 0 {
+			w.onChange(name, false)
+			continue
+		}
+		if event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+			w.addTree(name)
+		}
+		// A directory that appeared, vanished or was renamed takes everything below it along
+		// 나타나거나 사라지거나 이름이 바뀐 디렉토리는 그 아래의 모든 것을 함께 바꿈
+		w.onChange(name, event.Mask&(syscall.IN_CREATE|syscall.IN_DELETE|syscall.IN_MOVED_FROM|syscall.IN_MOVED_TO) != 0)
+	}
+}
+
+// Close stops the watcher.
+// Close는 감시자를 중지합니다.
+func (w *inotifyWatcher) Close() error {
+	return w.file.Close()
+}
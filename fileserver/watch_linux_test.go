@@ -0,0 +1,32 @@
+//go:build linux
+
+package fileserver
+
+import (
+	"net/http"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// TestInotifyOverflow tests that a queue overflow clears the whole cache.
+func TestInotifyOverflow(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+	cache := NewMemoryCache(MemoryCacheConfig{CheckInterval: -1})
+	h, err := New(http.Dir(dir), WithMemoryCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	serve(h, "/a.txt")
+	serve(h, "/sub/b.txt")
+	if entries := cache.Stats().Entries; entries != 2 {
+		t.Fatalf("entries = %d, want 2", entries)
+	}
+
+	w := &inotifyWatcher{dirs: map[int32]string{1: "/"}, onChange: cache.changed}
+	event := syscall.InotifyEvent{Wd: -1, Mask: syscall.IN_Q_OVERFLOW}
+	w.handle((*[syscall.SizeofInotifyEvent]byte)(unsafe.Pointer(&event))[:])
+	if entries := cache.Stats().Entries; entries != 0 {
+		t.Errorf("entries after overflow = %d, want 0", entries)
+	}
+}
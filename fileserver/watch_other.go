@@ -0,0 +1,9 @@
+//go:build !linux
+
+package fileserver
+
+// newWatcher starts a polling watcher for dir, since inotify is only available on Linux.
+// newWatcher는 inotify가 Linux에서만 제공되므로 dir에 대한 폴링 감시자를 시작합니다.
+func newWatcher(dir string, onChange changeFunc) (watcher, error) {
+	return newPollWatcher(dir, defaultPollInterval, onChange)
+}
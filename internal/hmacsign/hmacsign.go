@@ -0,0 +1,23 @@
+// Package hmacsign signs values with HMAC-SHA256 for the cookie and fileserver packages.
+// hmacsign 패키지는 cookie와 fileserver 패키지를 위해 값을 HMAC-SHA256으로 서명합니다.
+package hmacsign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// Sign generates an HMAC-SHA256 signature of value with key and returns it encoded with enc.
+// Sign은 value를 key로 HMAC-SHA256 서명한 후, enc로 인코딩된 문자열로 반환합니다.
+func Sign(key []byte, value string, enc *base64.Encoding) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(value))
+	return enc.EncodeToString(h.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of value with key encoded with enc, comparing in constant time.
+// Verify는 signature가 key로 서명하고 enc로 인코딩한 value의 서명인지 상수 시간 비교로 확인합니다.
+func Verify(key []byte, value, signature string, enc *base64.Encoding) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(key, value, enc)))
+}
@@ -0,0 +1,199 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DevNewbie1826/httperror"
+	"github.com/DevNewbie1826/webUtil/secure"
+)
+
+// Algorithm selects how requests are counted.
+// Algorithm은 요청을 계산하는 방식을 선택합니다.
+type Algorithm int
+
+const (
+	// TokenBucket refills Limit tokens per Period and allows bursts up to Burst requests.
+	// TokenBucket은 Period마다 Limit개의 토큰을 채우며 최대 Burst개의 요청까지 순간 허용합니다.
+	TokenBucket Algorithm = iota
+	// SlidingWindow allows Limit requests in any Period, estimated from the current and previous window counts.
+	// SlidingWindow는 현재와 이전 윈도우의 개수로 추정하여 임의의 Period 동안 Limit개의 요청을 허용합니다.
+	SlidingWindow
+)
+
+// Rule describes a rate limit evaluated by a Store.
+// Rule은 Store가 평가하는 속도 제한을 나타냅니다.
+type Rule struct {
+	// Limit is the number of requests allowed per Period. Middleware uses 60 if it is zero or negative.
+	// Limit는 Period마다 허용되는 요청 수입니다. 0 이하이면 Middleware는 60을 사용합니다.
+	Limit int
+	// Period is the time span Limit applies to. Middleware uses one minute if it is zero or negative.
+	// Period는 Limit가 적용되는 시간 범위입니다. 0 이하이면 Middleware는 1분을 사용합니다.
+	Period time.Duration
+	// Burst is the token bucket capacity. Defaults to Limit. It is ignored by SlidingWindow.
+	// Burst는 토큰 버킷의 용량입니다. 기본값은 Limit이며 SlidingWindow에서는 무시됩니다.
+	Burst int
+	// Algorithm is the counting algorithm.
+	// Algorithm은 계산 알고리즘입니다.
+	Algorithm Algorithm
+}
+
+// Result is the outcome of a Store.Take call.
+// Result는 Store.Take 호출의 결과입니다.
+type Result struct {
+	// Allowed reports whether the request may proceed.
+	// Allowed는 요청을 진행할 수 있는지 여부입니다.
+	Allowed bool
+	// Limit is the advertised request quota.
+	// Limit는 안내되는 요청 할당량입니다.
+	Limit int
+	// Remaining is the number of requests left in the current quota.
+	// Remaining은 현재 할당량에서 남은 요청 수입니다.
+	Remaining int
+	// Reset is the time until the quota is fully restored.
+	// Reset은 할당량이 완전히 복구될 때까지의 시간입니다.
+	Reset time.Duration
+	// RetryAfter is the time until the next request would be allowed. It is zero if Allowed is true.
+	// RetryAfter는 다음 요청이 허용될 때까지의 시간입니다. Allowed가 true이면 0입니다.
+	RetryAfter time.Duration
+}
+
+// Store keeps rate limit state. Implementations must evaluate and update the state for a key atomically,
+// so a Store backed by an external service (e.g. Redis) can be shared between several servers.
+// Store는 속도 제한 상태를 보관합니다. 구현체는 키에 대한 상태를 원자적으로 평가하고 갱신해야 하므로,
+// 외부 서비스(예: Redis)를 사용하는 Store를 여러 서버가 공유할 수 있습니다.
+type Store interface {
+	// Take consumes one request for key under rule and reports whether it is allowed.
+	// Take는 rule에 따라 key에 대한 요청 하나를 소비하고 허용 여부를 반환합니다.
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// KeyFunc extracts the key requests are counted by. Requests with an empty key are not limited.
+// KeyFunc는 요청을 계산할 기준 키를 추출합니다. 키가 비어 있는 요청은 제한되지 않습니다.
+type KeyFunc func(r *http.Request) string
+
+// KeyByIP counts requests per client IP address as resolved by secure.ClientIP.
+// Put secure.RealIP in front of the middleware when running behind proxies.
+// KeyByIP는 secure.ClientIP가 확인한 클라이언트 IP 주소별로 요청을 계산합니다.
+// 프록시 뒤에서 실행할 때는 미들웨어 앞에 secure.RealIP를 배치하세요.
+func KeyByIP() KeyFunc {
+	return func(r *http.Request) string {
+		return "ip:" + secure.ClientIP(r)
+	}
+}
+
+// KeyByHeader counts requests per value of the named header, such as an API key.
+// KeyByHeader는 API 키와 같이 지정한 헤더의 값별로 요청을 계산합니다.
+func KeyByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		if v := r.Header.Get(name); v != "" {
+			return "header:" + name + ":" + v
+		}
+		return ""
+	}
+}
+
+// KeyByCookie counts requests per value of the named cookie, such as a session ID.
+// KeyByCookie는 세션 ID와 같이 지정한 쿠키의 값별로 요청을 계산합니다.
+func KeyByCookie(name string) KeyFunc {
+	return func(r *http.Request) string {
+		if c, err := r.Cookie(name); err == nil && c.Value != "" {
+			return "cookie:" + name + ":" + c.Value
+		}
+		return ""
+	}
+}
+
+// Config is a configuration struct for the rate limiting middleware.
+// Config는 속도 제한 미들웨어를 위한 설정 구조체입니다.
+type Config struct {
+	// Rule is the rate limit applied to each key.
+	// Rule은 각 키에 적용되는 속도 제한입니다.
+	Rule
+	// Name prefixes every key, so several middlewares can share one Store with separate quotas.
+	// Name은 모든 키 앞에 붙어, 여러 미들웨어가 하나의 Store를 별도 할당량으로 공유할 수 있게 합니다.
+	Name string
+	// Key extracts the key requests are counted by. Defaults to KeyByIP.
+	// Key는 요청을 계산할 기준 키를 추출합니다. 기본값은 KeyByIP입니다.
+	Key KeyFunc
+	// Store keeps the rate limit state. Defaults to a new MemoryStore.
+	// Store는 속도 제한 상태를 보관합니다. 기본값은 새 MemoryStore입니다.
+	Store Store
+}
+
+// Default rule of Middleware, applied to a zero Limit or Period.
+// Limit 또는 Period가 0일 때 적용되는 Middleware의 기본 규칙입니다.
+const (
+	defaultLimit  = 60
+	defaultPeriod = time.Minute
+)
+
+// Middleware creates a rate limiting middleware. It sets the RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers on every limited response and rejects requests over the quota with
+// 429 Too Many Requests and a Retry-After header. If the Store fails, the error is logged and the request is allowed.
+// A zero Rule allows 60 requests per minute.
+// Middleware는 속도 제한 미들웨어를 생성합니다. 제한 대상 응답마다 RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset 헤더를 설정하며, 할당량을 초과한 요청은 Retry-After 헤더와 함께 429 Too Many Requests로 거부합니다.
+// Store가 실패하면 오류를 로깅하고 요청을 허용합니다. 제로 값 Rule은 분당 60개의 요청을 허용합니다.
+func Middleware(config Config) func(http.Handler) http.Handler {
+	rule := config.Rule
+	if rule.Limit <= 0 {
+		rule.Limit = defaultLimit
+	}
+	if rule.Period <= 0 {
+		rule.Period = defaultPeriod
+	}
+	if rule.Burst <= 0 {
+		rule.Burst = rule.Limit
+	}
+	keyFunc := config.Key
+	if keyFunc == nil {
+		keyFunc = KeyByIP()
+	}
+	store := config.Store
+	if store == nil {
+		store = NewMemoryStore(0)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := store.Take(r.Context(), config.Name+"|"+key, rule)
+			if err != nil {
+				log.Printf("ratelimit: store error: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(max(ceilSeconds(res.RetryAfter), 1)))
+				httperror.TooManyRequests(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ceilSeconds rounds a duration up to whole seconds.
+// ceilSeconds는 기간을 초 단위로 올림합니다.
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
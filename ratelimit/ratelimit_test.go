@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestStore creates a MemoryStore with a controllable clock.
+func newTestStore(now *time.Time) *MemoryStore {
+	s := NewMemoryStore(1)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+// TestTokenBucket tests burst consumption and refill of the token bucket algorithm.
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newTestStore(&now)
+	rule := Rule{Limit: 2, Period: time.Second, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		if res, _ := s.Take(context.Background(), "k", rule); !res.Allowed {
+			t.Fatalf("request %d: expected allowed", i)
+		}
+	}
+	res, _ := s.Take(context.Background(), "k", rule)
+	if res.Allowed || res.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("expected denial with 500ms retry, got %+v", res)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if res, _ := s.Take(context.Background(), "k", rule); !res.Allowed {
+		t.Fatalf("expected allowed after refill, got %+v", res)
+	}
+}
+
+// TestSlidingWindow tests that the previous window is weighted into the current one.
+func TestSlidingWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newTestStore(&now)
+	rule := Rule{Limit: 4, Period: 10 * time.Second, Algorithm: SlidingWindow}
+
+	for i := 0; i < 4; i++ {
+		if res, _ := s.Take(context.Background(), "k", rule); !res.Allowed {
+			t.Fatalf("request %d: expected allowed", i)
+		}
+	}
+	if res, _ := s.Take(context.Background(), "k", rule); res.Allowed {
+		t.Fatal("expected denial in full window")
+	}
+
+	// Halfway through the next window, half of the previous count still applies.
+	now = now.Add(15 * time.Second)
+	for i := 0; i < 2; i++ {
+		if res, _ := s.Take(context.Background(), "k", rule); !res.Allowed {
+			t.Fatalf("request %d: expected allowed in next window", i)
+		}
+	}
+	if res, _ := s.Take(context.Background(), "k", rule); res.Allowed {
+		t.Fatal("expected denial from weighted previous window")
+	}
+}
+
+// TestSlidingWindowRetryAfter tests that RetryAfter after a burst filling one window is the real wait.
+func TestSlidingWindowRetryAfter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newTestStore(&now)
+	rule := Rule{Limit: 4, Period: 10 * time.Second, Algorithm: SlidingWindow}
+
+	for i := 0; i < 4; i++ {
+		s.Take(context.Background(), "k", rule)
+	}
+	// The rest of this window, then a quarter of the next until 3 of the 4 requests remain weighted in.
+	res, _ := s.Take(context.Background(), "k", rule)
+	if res.Allowed || res.RetryAfter != 12500*time.Millisecond {
+		t.Fatalf("expected denial with 12.5s retry, got %+v", res)
+	}
+
+	now = now.Add(res.RetryAfter - time.Millisecond)
+	if res, _ := s.Take(context.Background(), "k", rule); res.Allowed {
+		t.Fatalf("expected denial before RetryAfter, got %+v", res)
+	}
+	now = now.Add(time.Millisecond)
+	if res, _ := s.Take(context.Background(), "k", rule); !res.Allowed {
+		t.Fatalf("expected allowed after RetryAfter, got %+v", res)
+	}
+}
+
+// TestMiddleware tests the rate limit headers and the 429 response.
+func TestMiddleware(t *testing.T) {
+	h := Middleware(Config{Rule: Rule{Limit: 1, Period: time.Minute}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "1" || rec.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("unexpected first response: %d %v", rec.Code, rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" {
+		t.Fatalf("unexpected second response: %d %v", rec.Code, rec.Header())
+	}
+}
+
+// TestMiddlewareDefaults tests that a zero Rule advertises the documented default of 60 requests per minute.
+func TestMiddlewareDefaults(t *testing.T) {
+	h := Middleware(Config{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Header().Get("RateLimit-Limit") != "60" || rec.Header().Get("RateLimit-Remaining") != "59" {
+		t.Fatalf("unexpected default quota: %v", rec.Header())
+	}
+}
@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"context"
+	"hash/maphash"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the number of Take calls on a shard between sweeps of expired entries.
+// sweepInterval은 만료된 항목을 정리하는 사이에 샤드에서 수행되는 Take 호출 횟수입니다.
+const sweepInterval = 1024
+
+// MemoryStore is an in-memory Store. Keys are spread over independently locked shards to reduce
+// lock contention, and expired entries are swept lazily.
+// MemoryStore는 메모리 기반 Store입니다. 잠금 경합을 줄이기 위해 키를 독립적으로 잠기는 샤드에 분산하며,
+// 만료된 항목은 필요할 때 정리됩니다.
+type MemoryStore struct {
+	// seed is the hash seed used to pick a shard.
+	// seed는 샤드를 선택하는 데 사용되는 해시 시드입니다.
+	seed maphash.Seed
+	// shards holds the state partitions.
+	// shards는 상태 분할을 보관합니다.
+	shards []*shard
+	// now returns the current time. It can be replaced in tests.
+	// now는 현재 시간을 반환합니다. 테스트에서 교체할 수 있습니다.
+	now func() time.Time
+}
+
+// shard is a single lock-protected partition of a MemoryStore.
+// shard는 잠금으로 보호되는 MemoryStore의 단일 분할입니다.
+type shard struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	ops     int
+}
+
+// entry holds the state of a single key.
+// entry는 단일 키의 상태를 보관합니다.
+type entry struct {
+	// tokens and last hold the token bucket state.
+	// tokens와 last는 토큰 버킷 상태를 보관합니다.
+	tokens float64
+	last   time.Time
+	// windowStart, prev and curr hold the sliding window state.
+	// windowStart, prev, curr는 슬라이딩 윈도우 상태를 보관합니다.
+	windowStart time.Time
+	prev, curr  int
+	// expires is the time after which the entry holds no information and can be removed.
+	// expires는 항목이 더 이상 정보를 갖지 않아 제거될 수 있는 시간입니다.
+	expires time.Time
+}
+
+// NewMemoryStore creates a MemoryStore with the given number of shards.
+// If shards is not positive, it defaults to four times GOMAXPROCS.
+// NewMemoryStore는 주어진 샤드 수로 MemoryStore를 생성합니다.
+// shards가 양수가 아니면 GOMAXPROCS의 네 배를 기본값으로 사용합니다.
+func NewMemoryStore(shards int) *MemoryStore {
+	if shards <= 0 {
+		shards = 4 * runtime.GOMAXPROCS(0)
+	}
+	s := &MemoryStore{seed: maphash.MakeSeed(), shards: make([]*shard, shards), now: time.Now}
+	for i := range s.shards {
+		s.shards[i] = &shard{entries: make(map[string]*entry)}
+	}
+	return s
+}
+
+// Take consumes one request for key under rule and reports whether it is allowed.
+// Take는 rule에 따라 key에 대한 요청 하나를 소비하고 허용 여부를 반환합니다.
+func (s *MemoryStore) Take(_ context.Context, key string, rule Rule) (Result, error) {
+	now := s.now()
+	sh := s.shards[maphash.String(s.seed, key)%uint64(len(s.shards))]
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	sh.ops++
+	if sh.ops >= sweepInterval {
+		sh.ops = 0
+		for k, e := range sh.entries {
+			if now.After(e.expires) {
+				delete(sh.entries, k)
+			}
+		}
+	}
+
+	e, ok := sh.entries[key]
+	if !ok {
+		e = &entry{}
+		sh.entries[key] = e
+	}
+	if rule.Algorithm == SlidingWindow {
+		return e.takeWindow(now, rule), nil
+	}
+	return e.takeToken(now, rule, !ok), nil
+}
+
+// takeToken applies the token bucket algorithm.
+// takeToken은 토큰 버킷 알고리즘을 적용합니다.
+func (e *entry) takeToken(now time.Time, rule Rule, fresh bool) Result {
+	capacity := float64(max(rule.Burst, 1))
+	rate := float64(rule.Limit) / rule.Period.Seconds() // tokens per second
+
+	if fresh {
+		e.tokens = capacity
+	} else {
+		e.tokens = math.Min(capacity, e.tokens+now.Sub(e.last).Seconds()*rate)
+	}
+	e.last = now
+
+	res := Result{Limit: int(capacity)}
+	if e.tokens >= 1 {
+		e.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - e.tokens) / rate)
+	}
+	res.Remaining = int(e.tokens)
+	res.Reset = seconds((capacity - e.tokens) / rate)
+	e.expires = now.Add(res.Reset)
+	return res
+}
+
+// takeWindow applies the sliding window counter algorithm.
+// takeWindow는 슬라이딩 윈도우 카운터 알고리즘을 적용합니다.
+func (e *entry) takeWindow(now time.Time, rule Rule) Result {
+	start := now.Truncate(rule.Period)
+	switch {
+	case start.Equal(e.windowStart):
+	case start.Sub(e.windowStart) == rule.Period:
+		e.prev, e.curr = e.curr, 0
+	default:
+		e.prev, e.curr = 0, 0
+	}
+	e.windowStart = start
+
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(rule.Period)
+	estimated := float64(e.prev)*weight + float64(e.curr)
+
+	res := Result{Limit: rule.Limit, Reset: rule.Period - elapsed}
+	if estimated+1 <= float64(rule.Limit) {
+		e.curr++
+		res.Allowed = true
+		res.Remaining = max(rule.Limit-int(math.Ceil(estimated+1)), 0)
+	} else if e.curr+1 > rule.Limit {
+		// The current window alone is full: wait for the next window, and then until enough of this one
+		// has slid out for one more request to fit.
+		// 현재 윈도우만으로 가득 찼으므로 다음 윈도우를 기다린 뒤, 요청 하나가 더 들어갈 만큼 이 윈도우가
+		// 밀려날 때까지 기다립니다.
+		fit := 1 - float64(rule.Limit-1)/float64(e.curr)
+		res.RetryAfter = rule.Period - elapsed + time.Duration(fit*float64(rule.Period))
+	} else {
+		// Wait until enough of the previous window has slid out for one more request to fit.
+		// 요청 하나가 더 들어갈 만큼 이전 윈도우가 밀려날 때까지 기다립니다.
+		fit := 1 - float64(rule.Limit-e.curr-1)/float64(e.prev)
+		res.RetryAfter = time.Duration(fit*float64(rule.Period)) - elapsed
+	}
+	e.expires = start.Add(2 * rule.Period)
+	return res
+}
+
+// seconds converts fractional seconds to a time.Duration.
+// seconds는 소수 초를 time.Duration으로 변환합니다.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
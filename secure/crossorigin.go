@@ -0,0 +1,125 @@
+package secure
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Cross-Origin-Opener-Policy values.
+// Cross-Origin-Opener-Policy 값입니다.
+const (
+	OpenerPolicySameOrigin            = "same-origin"
+	OpenerPolicySameOriginAllowPopups = "same-origin-allow-popups"
+	OpenerPolicyUnsafeNone            = "unsafe-none"
+)
+
+// Cross-Origin-Embedder-Policy values.
+// Cross-Origin-Embedder-Policy 값입니다.
+const (
+	EmbedderPolicyRequireCorp    = "require-corp"
+	EmbedderPolicyCredentialless = "credentialless"
+	EmbedderPolicyUnsafeNone     = "unsafe-none"
+)
+
+// Cross-Origin-Resource-Policy values.
+// Cross-Origin-Resource-Policy 값입니다.
+const (
+	ResourcePolicySameOrigin  = "same-origin"
+	ResourcePolicySameSite    = "same-site"
+	ResourcePolicyCrossOrigin = "cross-origin"
+)
+
+// CrossOriginConfig is a configuration struct for the CrossOriginIsolation middleware.
+// The zero value enables cross-origin isolation with COOP "same-origin" and COEP "require-corp",
+// which is what browsers require before exposing SharedArrayBuffer to a document.
+// CrossOriginConfig는 CrossOriginIsolation 미들웨어를 위한 설정 구조체입니다.
+// 제로 값은 COOP "same-origin"과 COEP "require-corp"로 교차 출처 격리를 활성화하며,
+// 이는 브라우저가 문서에 SharedArrayBuffer를 노출하기 위해 요구하는 조건입니다.
+type CrossOriginConfig struct {
+	// OpenerPolicy is the Cross-Origin-Opener-Policy value. Defaults to OpenerPolicySameOrigin.
+	// OpenerPolicy는 Cross-Origin-Opener-Policy 값입니다. 기본값은 OpenerPolicySameOrigin입니다.
+	OpenerPolicy string
+	// EmbedderPolicy is the Cross-Origin-Embedder-Policy value. Defaults to EmbedderPolicyRequireCorp.
+	// EmbedderPolicy는 Cross-Origin-Embedder-Policy 값입니다. 기본값은 EmbedderPolicyRequireCorp입니다.
+	EmbedderPolicy string
+	// ResourcePolicy, if set, is sent as Cross-Origin-Resource-Policy on the document itself.
+	// ResourcePolicy가 설정되면 문서 자체에 Cross-Origin-Resource-Policy로 전송됩니다.
+	ResourcePolicy string
+	// ReportOnly sends COOP and COEP through their -Report-Only headers so violations are reported but not enforced.
+	// ReportOnly는 COOP와 COEP를 -Report-Only 헤더로 전송하여 위반 사항을 강제하지 않고 보고만 합니다.
+	ReportOnly bool
+	// ReportTo is the Reporting API endpoint name added as the report-to parameter of COOP and COEP.
+	// ReportTo는 COOP와 COEP의 report-to 파라미터로 추가되는 Reporting API 엔드포인트 이름입니다.
+	ReportTo string
+	// ReportingEndpoints maps endpoint names to URLs and is sent as the Reporting-Endpoints header.
+	// ReportingEndpoints는 엔드포인트 이름을 URL에 매핑하며 Reporting-Endpoints 헤더로 전송됩니다.
+	ReportingEndpoints map[string]string
+}
+
+// CrossOriginIsolation is a middleware factory that sets the Cross-Origin-Opener-Policy and
+// Cross-Origin-Embedder-Policy headers required for cross-origin isolated documents.
+// Subresources served from the same origin must also carry Cross-Origin-Resource-Policy;
+// see fileserver.WithCrossOriginResourcePolicy.
+// CrossOriginIsolation은 교차 출처 격리 문서에 필요한 Cross-Origin-Opener-Policy와
+// Cross-Origin-Embedder-Policy 헤더를 설정하는 미들웨어 팩토리입니다.
+// 같은 출처에서 제공되는 하위 리소스에도 Cross-Origin-Resource-Policy가 있어야 합니다.
+// fileserver.WithCrossOriginResourcePolicy를 참고하세요.
+func CrossOriginIsolation(config CrossOriginConfig) func(http.Handler) http.Handler {
+	opener := config.OpenerPolicy
+	if opener == "" {
+		opener = OpenerPolicySameOrigin
+	}
+	embedder := config.EmbedderPolicy
+	if embedder == "" {
+		embedder = EmbedderPolicyRequireCorp
+	}
+	if config.ReportTo != "" {
+		param := "; report-to=" + strconv.Quote(config.ReportTo)
+		opener += param
+		embedder += param
+	}
+
+	openerHeader := "Cross-Origin-Opener-Policy"
+	embedderHeader := "Cross-Origin-Embedder-Policy"
+	if config.ReportOnly {
+		openerHeader += "-Report-Only"
+		embedderHeader += "-Report-Only"
+	}
+	endpoints := buildReportingEndpoints(config.ReportingEndpoints)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set(openerHeader, opener)
+			h.Set(embedderHeader, embedder)
+			if config.ResourcePolicy != "" {
+				h.Set("Cross-Origin-Resource-Policy", config.ResourcePolicy)
+			}
+			if endpoints != "" {
+				h.Set("Reporting-Endpoints", endpoints)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// buildReportingEndpoints constructs the Reporting-Endpoints header value, sorted by name for a stable output.
+// buildReportingEndpoints는 안정적인 출력을 위해 이름순으로 정렬된 Reporting-Endpoints 헤더 값을 생성합니다.
+func buildReportingEndpoints(endpoints map[string]string) string {
+	if len(endpoints) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(endpoints))
+	for name := range endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+strconv.Quote(endpoints[name]))
+	}
+	return strings.Join(parts, ", ")
+}
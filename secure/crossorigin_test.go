@@ -0,0 +1,60 @@
+package secure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCrossOriginIsolation tests the COOP, COEP, CORP and Reporting-Endpoints headers of each configuration.
+func TestCrossOriginIsolation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config CrossOriginConfig
+		want   map[string]string
+	}{
+		{"defaults", CrossOriginConfig{}, map[string]string{
+			"Cross-Origin-Opener-Policy":   "same-origin",
+			"Cross-Origin-Embedder-Policy": "require-corp",
+			"Cross-Origin-Resource-Policy": "",
+			"Reporting-Endpoints":          "",
+		}},
+		{"custom policies", CrossOriginConfig{
+			OpenerPolicy:   OpenerPolicySameOriginAllowPopups,
+			EmbedderPolicy: EmbedderPolicyCredentialless,
+			ResourcePolicy: ResourcePolicySameSite,
+		}, map[string]string{
+			"Cross-Origin-Opener-Policy":   "same-origin-allow-popups",
+			"Cross-Origin-Embedder-Policy": "credentialless",
+			"Cross-Origin-Resource-Policy": "same-site",
+		}},
+		{"reporting", CrossOriginConfig{
+			ReportTo:           "coop",
+			ReportingEndpoints: map[string]string{"coop": "https://example.com/coop", "csp": "https://example.com/csp"},
+		}, map[string]string{
+			"Cross-Origin-Opener-Policy":   `same-origin; report-to="coop"`,
+			"Cross-Origin-Embedder-Policy": `require-corp; report-to="coop"`,
+			"Reporting-Endpoints":          `coop="https://example.com/coop", csp="https://example.com/csp"`,
+		}},
+		{"report only", CrossOriginConfig{ReportOnly: true, ReportTo: "coop", ResourcePolicy: ResourcePolicyCrossOrigin}, map[string]string{
+			"Cross-Origin-Opener-Policy-Report-Only":   `same-origin; report-to="coop"`,
+			"Cross-Origin-Embedder-Policy-Report-Only": `require-corp; report-to="coop"`,
+			"Cross-Origin-Opener-Policy":               "",
+			"Cross-Origin-Embedder-Policy":             "",
+			"Cross-Origin-Resource-Policy":             "cross-origin",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := CrossOriginIsolation(tt.config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			for name, want := range tt.want {
+				if got := rec.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package secure
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/DevNewbie1826/httperror"
+)
+
+// Limits holds the body size and deadline limits applied by the RequestGuard middleware.
+// Zero values disable the corresponding limit.
+// Limits는 RequestGuard 미들웨어가 적용하는 본문 크기 및 기한 제한을 보관합니다.
+// 0 값은 해당 제한을 비활성화합니다.
+type Limits struct {
+	// MaxBodyBytes is the maximum request body size. Larger bodies are answered with 413 Payload Too Large.
+	// MaxBodyBytes는 최대 요청 본문 크기입니다. 이보다 큰 본문은 413 Payload Too Large로 응답합니다.
+	MaxBodyBytes int64
+	// ReadTimeout is the deadline for reading the request body. Slow bodies are answered with 408 Request Timeout.
+	// ReadTimeout은 요청 본문을 읽는 기한입니다. 느린 본문은 408 Request Timeout으로 응답합니다.
+	ReadTimeout time.Duration
+	// WriteTimeout is the deadline for writing the response.
+	// WriteTimeout은 응답을 쓰는 기한입니다.
+	WriteTimeout time.Duration
+	// HandlerTimeout is the deadline set on the request context. The handler is not interrupted: if it
+	// returns without writing anything once the deadline expired, 503 Service Unavailable is returned,
+	// but a response it already started, even only its header, is kept as is.
+	// HandlerTimeout은 요청 컨텍스트에 설정되는 기한입니다. 핸들러를 중단하지는 않습니다. 기한이 지난 뒤
+	// 핸들러가 아무것도 쓰지 않고 종료하면 503 Service Unavailable을 반환하지만, 이미 시작된 응답은
+	// 헤더만 쓴 경우라도 그대로 유지됩니다.
+	HandlerTimeout time.Duration
+}
+
+// RouteLimits applies Limits to a path and everything below it.
+// RouteLimits는 경로와 그 하위 경로 전체에 Limits를 적용합니다.
+type RouteLimits struct {
+	// Path is the path the limits apply to, e.g. "/upload".
+	// Path는 제한이 적용되는 경로입니다(예: "/upload").
+	Path string
+	Limits
+}
+
+// GuardConfig is a configuration struct for the RequestGuard middleware.
+// GuardConfig는 RequestGuard 미들웨어를 위한 설정 구조체입니다.
+type GuardConfig struct {
+	// Limits are the default limits for requests that match no route.
+	// Limits는 어떤 경로에도 일치하지 않는 요청에 대한 기본 제한입니다.
+	Limits
+	// Routes lists per-route limits. The first matching entry wins.
+	// Routes는 경로별 제한 목록입니다. 처음 일치하는 항목이 적용됩니다.
+	Routes []RouteLimits
+}
+
+// RequestGuard is a middleware factory that protects handlers from oversized and slow request bodies.
+// Bodies declaring a Content-Length over the limit are rejected before the handler runs; other bodies are wrapped
+// with http.MaxBytesReader. Read and write deadlines are set on the connection through http.ResponseController.
+// When reading the body fails because of a limit, the handler's error response is replaced with a consistent
+// 413 or 408 response rendered through httperror.
+// RequestGuard는 너무 크거나 느린 요청 본문으로부터 핸들러를 보호하는 미들웨어 팩토리입니다.
+// 제한을 넘는 Content-Length를 선언한 본문은 핸들러 실행 전에 거부되며, 나머지 본문은 http.MaxBytesReader로 감싸집니다.
+// 읽기 및 쓰기 기한은 http.ResponseController를 통해 연결에 설정됩니다.
+// 제한 때문에 본문 읽기가 실패하면 핸들러의 오류 응답은 httperror로 렌더링되는 일관된 413 또는 408 응답으로 대체됩니다.
+func RequestGuard(config GuardConfig) func(http.Handler) http.Handler {
+	routes := make([]RouteLimits, len(config.Routes))
+	copy(routes, config.Routes)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limits := config.Limits
+			for _, route := range routes {
+				if pathMatches(r.URL.Path, route.Path) {
+					limits = route.Limits
+					break
+				}
+			}
+
+			if limits.MaxBodyBytes > 0 && r.ContentLength > limits.MaxBodyBytes {
+				httperror.PayloadTooLarge(w, r)
+				return
+			}
+
+			gw := &guardWriter{ResponseWriter: w, r: r}
+			rc := http.NewResponseController(w)
+			now := time.Now()
+			if limits.ReadTimeout > 0 {
+				rc.SetReadDeadline(now.Add(limits.ReadTimeout))
+				defer rc.SetReadDeadline(time.Time{})
+			}
+			if limits.WriteTimeout > 0 {
+				rc.SetWriteDeadline(now.Add(limits.WriteTimeout))
+				defer rc.SetWriteDeadline(time.Time{})
+			}
+			if limits.HandlerTimeout > 0 {
+				ctx, cancel := context.WithTimeout(r.Context(), limits.HandlerTimeout)
+				defer cancel()
+				r = r.WithContext(ctx)
+				gw.r = r
+			}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				body := r.Body
+				if limits.MaxBodyBytes > 0 {
+					body = http.MaxBytesReader(w, body, limits.MaxBodyBytes)
+				}
+				r.Body = &guardBody{ReadCloser: body, gw: gw}
+			}
+
+			next.ServeHTTP(gw, r)
+
+			if gw.wroteHeader {
+				return
+			}
+			if gw.failure != nil {
+				gw.renderFailure()
+			} else if errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+				httperror.ServiceUnavailable(w, r)
+			}
+		})
+	}
+}
+
+// guardWriter wraps an http.ResponseWriter to replace error responses caused by a body limit.
+// guardWriter는 본문 제한으로 인한 오류 응답을 대체하기 위해 http.ResponseWriter를 래핑합니다.
+type guardWriter struct {
+	http.ResponseWriter
+	// r is the request being served.
+	// r은 처리 중인 요청입니다.
+	r *http.Request
+	// failure is the error response to send when the body could not be read because of a limit.
+	// failure는 제한 때문에 본문을 읽지 못했을 때 보낼 오류 응답입니다.
+	failure func(w http.ResponseWriter, r *http.Request, message ...string)
+	// wroteHeader reports whether a status has been written.
+	// wroteHeader는 상태 코드가 기록되었는지 여부입니다.
+	wroteHeader bool
+	// discard drops the handler's writes after the failure response was sent.
+	// discard는 실패 응답을 보낸 후 핸들러의 쓰기를 버립니다.
+	discard bool
+}
+
+// WriteHeader writes the status, or the failure response if the handler reports an error after a body limit was hit.
+// WriteHeader는 상태 코드를 쓰며, 본문 제한에 걸린 뒤 핸들러가 오류를 보고하면 실패 응답을 씁니다.
+func (gw *guardWriter) WriteHeader(code int) {
+	if gw.wroteHeader {
+		return
+	}
+	gw.wroteHeader = true
+	if gw.failure != nil && code >= http.StatusBadRequest {
+		gw.discard = true
+		gw.renderFailure()
+		return
+	}
+	gw.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the response body unless it is being discarded.
+// Write는 버려지는 중이 아니라면 응답 본문을 씁니다.
+func (gw *guardWriter) Write(b []byte) (int, error) {
+	if !gw.wroteHeader {
+		gw.WriteHeader(http.StatusOK)
+	}
+	if gw.discard {
+		return len(b), nil
+	}
+	return gw.ResponseWriter.Write(b)
+}
+
+// ReadFrom copies the response body through the underlying writer, so sendfile remains available.
+// ReadFrom은 내부 writer를 통해 응답 본문을 복사하므로 sendfile을 계속 사용할 수 있습니다.
+func (gw *guardWriter) ReadFrom(src io.Reader) (int64, error) {
+	if !gw.wroteHeader {
+		gw.WriteHeader(http.StatusOK)
+	}
+	if gw.discard {
+		return io.Copy(io.Discard, src)
+	}
+	return io.Copy(gw.ResponseWriter, src)
+}
+
+// Flush sends buffered data to the client unless the response is being discarded.
+// Flush는 응답이 버려지는 중이 아니라면 버퍼링된 데이터를 클라이언트로 보냅니다.
+func (gw *guardWriter) Flush() {
+	if !gw.wroteHeader {
+		gw.WriteHeader(http.StatusOK)
+	}
+	if !gw.discard {
+		http.NewResponseController(gw.ResponseWriter).Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection. No response is written by the guard afterwards.
+// Hijack은 핸들러가 연결을 넘겨받게 합니다. 이후 가드는 응답을 쓰지 않습니다.
+func (gw *guardWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(gw.ResponseWriter).Hijack()
+	if err == nil {
+		gw.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+// Unwrap은 http.ResponseController를 위해 내부 http.ResponseWriter를 반환합니다.
+func (gw *guardWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+// renderFailure sends the failure response through httperror.
+// renderFailure는 httperror를 통해 실패 응답을 보냅니다.
+func (gw *guardWriter) renderFailure() {
+	gw.wroteHeader = true
+	gw.ResponseWriter.Header().Del("Content-Length")
+	gw.failure(gw.ResponseWriter, gw.r)
+}
+
+// guardBody wraps the request body to record read failures caused by a limit.
+// guardBody는 제한으로 인한 읽기 실패를 기록하기 위해 요청 본문을 래핑합니다.
+type guardBody struct {
+	io.ReadCloser
+	gw *guardWriter
+}
+
+// Read reads from the body and records size and deadline failures.
+// Read는 본문을 읽고 크기 및 기한 실패를 기록합니다.
+func (b *guardBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.gw.failure == nil {
+		var maxErr *http.MaxBytesError
+		var netErr net.Error
+		switch {
+		case errors.As(err, &maxErr):
+			b.gw.failure = httperror.PayloadTooLarge
+		case errors.Is(err, os.ErrDeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+			b.gw.failure = httperror.RequestTimeout
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,124 @@
+package secure
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// timeoutBody is a request body whose reads fail with a deadline error, as a connection with an expired read deadline does.
+type timeoutBody struct{}
+
+func (timeoutBody) Read([]byte) (int, error) { return 0, os.ErrDeadlineExceeded }
+
+// TestRequestGuard tests the 413, 408 and 503 responses and what reaches the handler otherwise.
+func TestRequestGuard(t *testing.T) {
+	mw := RequestGuard(GuardConfig{
+		Limits: Limits{MaxBodyBytes: 8},
+		Routes: []RouteLimits{{Path: "/slow", Limits: Limits{HandlerTimeout: 10 * time.Millisecond}}},
+	})
+	readBody := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, "bad body", http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+
+	tests := []struct {
+		name string
+		body io.Reader
+		size int64
+		want int
+	}{
+		{"small body", strings.NewReader("1234"), 4, http.StatusOK},
+		{"declared length over the limit", strings.NewReader("123456789"), 9, http.StatusRequestEntityTooLarge},
+		{"chunked body over the limit", strings.NewReader("123456789"), -1, http.StatusRequestEntityTooLarge},
+		{"read deadline", timeoutBody{}, -1, http.StatusRequestTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", tt.body)
+			req.ContentLength = tt.size
+			rec := httptest.NewRecorder()
+			readBody.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want != http.StatusOK && strings.Contains(rec.Body.String(), "bad body") {
+				t.Errorf("handler error body was not replaced: %q", rec.Body.String())
+			}
+		})
+	}
+
+	// A handler giving up after the deadline gets 503, one that already answered keeps its response
+	// 기한이 지나 포기한 핸들러는 503을 받고, 이미 응답한 핸들러는 응답을 유지
+	wait := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("early") {
+			w.WriteHeader(http.StatusAccepted)
+		}
+		<-r.Context().Done()
+	}))
+	for target, want := range map[string]int{"/slow": http.StatusServiceUnavailable, "/slow?early": http.StatusAccepted} {
+		rec := httptest.NewRecorder()
+		wait.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s: status = %d, want %d", target, rec.Code, want)
+		}
+	}
+	rec := httptest.NewRecorder()
+	wait.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(canceledContext()))
+	if rec.Code != http.StatusOK {
+		t.Errorf("canceled request: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+// canceledContext returns a context that is already canceled, not timed out.
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+// TestRequestGuardWriterInterfaces tests that the guarded writer keeps flushing, sendfile and hijacking available.
+func TestRequestGuardWriterInterfaces(t *testing.T) {
+	mw := RequestGuard(GuardConfig{Limits: Limits{MaxBodyBytes: 8}})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(io.ReaderFrom); !ok {
+			t.Error("writer does not implement io.ReaderFrom")
+		}
+		io.Copy(w, strings.NewReader("streamed"))
+		w.(http.Flusher).Flush()
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !rec.Flushed || rec.Body.String() != "streamed" {
+		t.Errorf("flushed = %v, body = %q", rec.Flushed, rec.Body.String())
+	}
+
+	srv := httptest.NewServer(mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 299 Hijacked\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+		rw.Flush()
+	})))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 299 {
+		t.Errorf("hijacked status = %d, want 299", resp.StatusCode)
+	}
+}
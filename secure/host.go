@@ -0,0 +1,106 @@
+package secure
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/DevNewbie1826/httperror"
+)
+
+// hostContextKey is an unexported type used as a key for context values.
+// hostContextKey는 컨텍스트 값의 키로 사용되는 비공개 타입입니다.
+type hostContextKey struct{}
+
+// HostConfig is a configuration struct for the AllowedHosts middleware.
+// HostConfig는 AllowedHosts 미들웨어를 위한 설정 구조체입니다.
+type HostConfig struct {
+	// Hosts lists the accepted hosts. An entry is either an exact host ("example.com"), which matches
+	// on any port, a host with a port ("example.com:8443"), which matches only that port, or a wildcard
+	// ("*.example.com"), which matches any subdomain but not the apex domain.
+	// Hosts는 허용되는 호스트 목록입니다. 각 항목은 모든 포트에 일치하는 정확한 호스트("example.com"),
+	// 해당 포트에만 일치하는 포트 포함 호스트("example.com:8443"), 또는 최상위 도메인을 제외한 모든
+	// 서브도메인에 일치하는 와일드카드("*.example.com")입니다.
+	Hosts []string
+	// StatusCode is the status returned on mismatch: 400 Bad Request (default) or 421 Misdirected Request.
+	// StatusCode는 불일치 시 반환되는 상태 코드로, 400 Bad Request(기본값) 또는 421 Misdirected Request입니다.
+	StatusCode int
+}
+
+// AllowedHosts is a middleware factory that rejects requests whose host is not in the allowlist.
+// The host is taken from ClientHost, so X-Forwarded-Host is only considered when it was sent by a
+// trusted proxy and the RealIP middleware runs first. The validated host is stored in the request
+// context and can be retrieved with GetHost, e.g. to build absolute URLs.
+// AllowedHosts는 호스트가 허용 목록에 없는 요청을 거부하는 미들웨어 팩토리입니다.
+// 호스트는 ClientHost에서 가져오므로, X-Forwarded-Host는 신뢰 프록시가 보냈고 RealIP 미들웨어가
+// 먼저 실행된 경우에만 고려됩니다. 검증된 호스트는 요청 컨텍스트에 저장되며, 절대 URL 생성 등을 위해
+// GetHost로 가져올 수 있습니다.
+func AllowedHosts(config HostConfig) func(http.Handler) http.Handler {
+	patterns := make([]string, len(config.Hosts))
+	for i, h := range config.Hosts {
+		patterns[i] = normalizeHost(h)
+	}
+	reject := httperror.BadRequest
+	if config.StatusCode == http.StatusMisdirectedRequest {
+		reject = httperror.MisdirectedRequest
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host := normalizeHost(ClientHost(r))
+			if !matchHost(host, patterns) {
+				reject(w, r)
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), hostContextKey{}, host))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetHost retrieves the validated host from the context. It returns an empty string if the
+// AllowedHosts middleware is not in the handler chain.
+// GetHost는 컨텍스트에서 검증된 호스트를 가져옵니다. AllowedHosts 미들웨어가 핸들러 체인에 없으면
+// 빈 문자열을 반환합니다.
+func GetHost(ctx context.Context) string {
+	host, _ := ctx.Value(hostContextKey{}).(string)
+	return host
+}
+
+// matchHost reports whether host matches one of the patterns.
+// matchHost는 host가 패턴 중 하나와 일치하는지 여부를 반환합니다.
+func matchHost(host string, patterns []string) bool {
+	if host == "" {
+		return false
+	}
+	name, port := splitHostPort(host)
+	for _, pattern := range patterns {
+		pName, pPort := splitHostPort(pattern)
+		if pPort != "" && pPort != port {
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(pName, "*."); ok {
+			if strings.HasSuffix(name, "."+suffix) {
+				return true
+			}
+		} else if name == pName {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHost lower-cases a host and removes a trailing dot from a fully qualified name.
+// normalizeHost는 호스트를 소문자로 바꾸고 정규화된 이름의 마지막 점을 제거합니다.
+func normalizeHost(host string) string {
+	name, port := splitHostPort(host)
+	name = strings.TrimSuffix(name, ".")
+	if port == "" {
+		if strings.Contains(name, ":") {
+			return "[" + name + "]"
+		}
+		return name
+	}
+	return net.JoinHostPort(name, port)
+}
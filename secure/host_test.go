@@ -0,0 +1,93 @@
+package secure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMatchHost tests host patterns with ports, wildcards, IPv6 addresses and trailing dots.
+func TestMatchHost(t *testing.T) {
+	patterns := []string{"Example.com", "api.example.com:8443", "*.apps.example.com.", "[2001:db8::1]", "127.0.0.1:8080"}
+	for i, p := range patterns {
+		patterns[i] = normalizeHost(p)
+	}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"EXAMPLE.COM:443", true},
+		{"example.com.", true},
+		{"www.example.com", false},
+		{"api.example.com:8443", true},
+		{"api.example.com", false},
+		{"api.example.com:443", false},
+		{"a.apps.example.com", true},
+		{"a.b.apps.example.com:8080", true},
+		{"apps.example.com", false},
+		{"evilapps.example.com", false},
+		{"[2001:db8::1]:443", true},
+		{"[2001:DB8::1]", true},
+		{"[2001:db8::2]", false},
+		{"127.0.0.1:8080", true},
+		{"127.0.0.1", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := matchHost(normalizeHost(tt.host), patterns); got != tt.want {
+			t.Errorf("matchHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+// TestNormalizeHost tests case, trailing dot and IPv6 normalization.
+func TestNormalizeHost(t *testing.T) {
+	for host, want := range map[string]string{
+		"Example.COM.":      "example.com",
+		"example.com.:8080": "example.com:8080",
+		"[2001:DB8::1]:443": "[2001:db8::1]:443",
+		"[2001:db8::1]":     "[2001:db8::1]",
+		"2001:db8::1":       "[2001:db8::1]",
+		"192.0.2.1:80":      "192.0.2.1:80",
+		"":                  "",
+	} {
+		if got := normalizeHost(host); got != want {
+			t.Errorf("normalizeHost(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+// TestAllowedHosts tests the rejection status and the host stored for GetHost.
+func TestAllowedHosts(t *testing.T) {
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = GetHost(r.Context())
+	})
+
+	for _, tt := range []struct {
+		status int
+		want   int
+	}{{0, http.StatusBadRequest}, {http.StatusMisdirectedRequest, http.StatusMisdirectedRequest}} {
+		h := AllowedHosts(HostConfig{Hosts: []string{"*.example.com"}, StatusCode: tt.status})(next)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://evil.test/", nil))
+		if rec.Code != tt.want {
+			t.Errorf("StatusCode %d: status = %d, want %d", tt.status, rec.Code, tt.want)
+		}
+
+		got = ""
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://WWW.example.com./", nil))
+		if rec.Code != http.StatusOK || got != "www.example.com" {
+			t.Errorf("status = %d, GetHost = %q, want %d and %q", rec.Code, got, http.StatusOK, "www.example.com")
+		}
+	}
+
+	if host := GetHost(context.Background()); host != "" {
+		t.Errorf("GetHost without AllowedHosts = %q, want empty", host)
+	}
+}
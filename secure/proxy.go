@@ -0,0 +1,290 @@
+package secure
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientContextKey is an unexported type used as a key for context values.
+// clientContextKey는 컨텍스트 값의 키로 사용되는 비공개 타입입니다.
+type clientContextKey struct{}
+
+// clientInfo holds the client information resolved by the RealIP middleware.
+// clientInfo는 RealIP 미들웨어가 확인한 클라이언트 정보를 보관합니다.
+type clientInfo struct {
+	// ip is the resolved client IP address.
+	// ip는 확인된 클라이언트 IP 주소입니다.
+	ip netip.Addr
+	// scheme is the resolved request scheme, either "http" or "https".
+	// scheme은 확인된 요청 스킴으로, "http" 또는 "https"입니다.
+	scheme string
+	// host is the resolved host the client addressed, including an optional port.
+	// host는 클라이언트가 요청한 호스트로, 선택적으로 포트를 포함합니다.
+	host string
+}
+
+// ProxyConfig is a configuration struct for the RealIP middleware.
+// ProxyConfig는 RealIP 미들웨어를 위한 설정 구조체입니다.
+type ProxyConfig struct {
+	// TrustedProxies is a list of CIDRs (e.g. "10.0.0.0/8") or single IP addresses whose forwarding headers are honored.
+	// Headers sent by any other peer are ignored.
+	// TrustedProxies는 전달 헤더를 신뢰할 CIDR(예: "10.0.0.0/8") 또는 단일 IP 주소 목록입니다.
+	// 그 외의 상대가 보낸 헤더는 무시됩니다.
+	TrustedProxies []string
+}
+
+// RealIP is a middleware factory that resolves the real client IP address and request scheme behind trusted proxies.
+// Forwarded, X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host and X-Real-IP are only honored when the immediate peer is in
+// one of the trusted networks. The address chain is walked from right to left, skipping trusted proxies,
+// so clients cannot spoof their address by prepending values. The result is available through ClientIP,
+// ClientScheme and ClientHost.
+// It panics if a trusted proxy entry cannot be parsed.
+// RealIP는 신뢰할 수 있는 프록시 뒤에서 실제 클라이언트 IP 주소와 요청 스킴을 확인하는 미들웨어 팩토리입니다.
+// Forwarded, X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host, X-Real-IP는 직접 연결된 상대가 신뢰 네트워크에 속할 때만 반영됩니다.
+// 주소 체인은 오른쪽에서 왼쪽으로 신뢰 프록시를 건너뛰며 탐색하므로, 클라이언트가 값을 앞에 추가하여 주소를 위조할 수 없습니다.
+// 결과는 ClientIP, ClientScheme, ClientHost로 확인할 수 있습니다. 신뢰 프록시 항목을 해석할 수 없으면 panic을 발생시킵니다.
+func RealIP(config ProxyConfig) func(http.Handler) http.Handler {
+	trusted := make([]netip.Prefix, 0, len(config.TrustedProxies))
+	for _, entry := range config.TrustedProxies {
+		prefix, err := parsePrefix(entry)
+		if err != nil {
+			panic(fmt.Sprintf("secure: invalid trusted proxy %q: %v", entry, err))
+		}
+		trusted = append(trusted, prefix)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info := resolveClient(r, trusted)
+			r = r.WithContext(context.WithValue(r.Context(), clientContextKey{}, info))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the client IP address resolved by the RealIP middleware.
+// If the middleware is not in the handler chain, it falls back to the host part of r.RemoteAddr.
+// ClientIP는 RealIP 미들웨어가 확인한 클라이언트 IP 주소를 반환합니다.
+// 미들웨어가 핸들러 체인에 없으면 r.RemoteAddr의 호스트 부분을 반환합니다.
+func ClientIP(r *http.Request) string {
+	if info, ok := r.Context().Value(clientContextKey{}).(*clientInfo); ok && info.ip.IsValid() {
+		return info.ip.String()
+	}
+	if addr, ok := peerAddr(r); ok {
+		return addr.String()
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ClientScheme returns the request scheme ("http" or "https") resolved by the RealIP middleware.
+// If the middleware is not in the handler chain, it is derived from r.TLS.
+// ClientScheme은 RealIP 미들웨어가 확인한 요청 스킴("http" 또는 "https")을 반환합니다.
+// 미들웨어가 핸들러 체인에 없으면 r.TLS로부터 결정됩니다.
+func ClientScheme(r *http.Request) string {
+	if info, ok := r.Context().Value(clientContextKey{}).(*clientInfo); ok {
+		return info.scheme
+	}
+	return connScheme(r)
+}
+
+// ClientHost returns the host the client addressed, as resolved by the RealIP middleware from the
+// Forwarded host parameter or X-Forwarded-Host of trusted proxies. Otherwise it returns r.Host.
+// ClientHost는 RealIP 미들웨어가 신뢰 프록시의 Forwarded host 파라미터나 X-Forwarded-Host로부터 확인한,
+// 클라이언트가 요청한 호스트를 반환합니다. 그렇지 않으면 r.Host를 반환합니다.
+func ClientHost(r *http.Request) string {
+	if info, ok := r.Context().Value(clientContextKey{}).(*clientInfo); ok {
+		return info.host
+	}
+	return r.Host
+}
+
+// resolveClient determines the client information of the request from the peer address and forwarding headers.
+// resolveClient는 상대 주소와 전달 헤더로부터 요청의 클라이언트 정보를 결정합니다.
+func resolveClient(r *http.Request, trusted []netip.Prefix) *clientInfo {
+	info := &clientInfo{scheme: connScheme(r), host: r.Host}
+	peer, ok := peerAddr(r)
+	if !ok {
+		return info
+	}
+	info.ip = peer
+	if !isTrusted(peer, trusted) {
+		return info
+	}
+
+	if values := r.Header.Values("Forwarded"); len(values) > 0 {
+		elements := parseForwarded(values)
+		hops := make([]string, len(elements))
+		for i, e := range elements {
+			hops[i] = e["for"]
+		}
+		ip, idx := walkChain(peer, hops, trusted)
+		info.ip = ip
+		if idx >= 0 {
+			if proto := normalizeScheme(elements[idx]["proto"]); proto != "" {
+				info.scheme = proto
+			}
+			if host := elements[idx]["host"]; host != "" {
+				info.host = host
+			}
+		}
+		return info
+	}
+
+	protos := splitList(r.Header.Values("X-Forwarded-Proto"))
+	hosts := splitList(r.Header.Values("X-Forwarded-Host"))
+	if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		hops := splitList(values)
+		ip, idx := walkChain(peer, hops, trusted)
+		info.ip = ip
+		// Use the proto and host recorded for the same hop when the lists line up, otherwise the ones set by the nearest proxy.
+		// 목록의 길이가 같으면 같은 홉의 proto와 host를, 그렇지 않으면 가장 가까운 프록시가 설정한 값을 사용합니다.
+		if idx >= 0 && len(protos) == len(hops) {
+			protos = protos[idx : idx+1]
+		}
+		if idx >= 0 && len(hosts) == len(hops) {
+			hosts = hosts[idx : idx+1]
+		}
+	} else if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		if addr, ok := parseNode(realIP); ok {
+			info.ip = addr
+		}
+	}
+	if len(protos) > 0 {
+		if proto := normalizeScheme(protos[len(protos)-1]); proto != "" {
+			info.scheme = proto
+		}
+	}
+	if len(hosts) > 0 {
+		info.host = hosts[len(hosts)-1]
+	}
+	return info
+}
+
+// walkChain walks the forwarded address chain from right to left and returns the first address that is not
+// a trusted proxy, along with its index. It stops at the first malformed entry.
+// The index is -1 if no entry from the chain was used.
+// walkChain은 전달된 주소 체인을 오른쪽에서 왼쪽으로 탐색하여 신뢰 프록시가 아닌 첫 주소와 그 인덱스를 반환합니다.
+// 잘못된 형식의 항목을 만나면 중단합니다. 체인의 항목이 사용되지 않았다면 인덱스는 -1입니다.
+func walkChain(peer netip.Addr, hops []string, trusted []netip.Prefix) (netip.Addr, int) {
+	client, idx := peer, -1
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := parseNode(hops[i])
+		if !ok {
+			break
+		}
+		client, idx = addr, i
+		if !isTrusted(addr, trusted) {
+			break
+		}
+	}
+	return client, idx
+}
+
+// parseForwarded parses the RFC 7239 Forwarded header values into a list of lower-cased parameter maps.
+// parseForwarded는 RFC 7239 Forwarded 헤더 값을 소문자 파라미터 맵의 목록으로 해석합니다.
+func parseForwarded(values []string) []map[string]string {
+	var elements []map[string]string
+	for _, element := range splitList(values) {
+		params := make(map[string]string)
+		for _, pair := range strings.Split(element, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			params[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+		elements = append(elements, params)
+	}
+	return elements
+}
+
+// splitList splits comma-separated header values into trimmed, non-empty items.
+// splitList는 쉼표로 구분된 헤더 값을 공백이 제거된 비어 있지 않은 항목으로 분리합니다.
+func splitList(values []string) []string {
+	var items []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// parseNode parses an address that may carry a port or IPv6 brackets, such as "[2001:db8::1]:4711".
+// parseNode는 "[2001:db8::1]:4711"처럼 포트나 IPv6 대괄호를 포함할 수 있는 주소를 해석합니다.
+func parseNode(s string) (netip.Addr, bool) {
+	s = strings.Trim(strings.TrimSpace(s), `"`)
+	if addrPort, err := netip.ParseAddrPort(s); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// parsePrefix parses a CIDR or a single IP address into a netip.Prefix.
+// parsePrefix는 CIDR 또는 단일 IP 주소를 netip.Prefix로 해석합니다.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// isTrusted reports whether addr belongs to one of the trusted networks.
+// isTrusted는 addr이 신뢰 네트워크 중 하나에 속하는지 여부를 반환합니다.
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// peerAddr returns the IP address of the immediate peer from r.RemoteAddr.
+// peerAddr는 r.RemoteAddr로부터 직접 연결된 상대의 IP 주소를 반환합니다.
+func peerAddr(r *http.Request) (netip.Addr, bool) {
+	return parseNode(r.RemoteAddr)
+}
+
+// connScheme returns the scheme of the connection the request arrived on.
+// connScheme은 요청이 도착한 연결의 스킴을 반환합니다.
+func connScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// normalizeScheme lower-cases a forwarded proto value and returns "" if it is not http or https.
+// normalizeScheme은 전달된 proto 값을 소문자로 바꾸며, http 또는 https가 아니면 ""를 반환합니다.
+func normalizeScheme(proto string) string {
+	switch proto = strings.ToLower(strings.TrimSpace(proto)); proto {
+	case "http", "https":
+		return proto
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package secure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRealIP tests client IP and scheme resolution behind trusted and untrusted peers.
+func TestRealIP(t *testing.T) {
+	mw := RealIP(ProxyConfig{TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1"}})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		wantIP     string
+		wantScheme string
+	}{
+		{"untrusted peer ignores headers", "203.0.113.9:1234", map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Forwarded-Proto": "https"}, "203.0.113.9", "http"},
+		{"trusted peer uses X-Forwarded-For", "10.1.2.3:1234", map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Forwarded-Proto": "https"}, "1.2.3.4", "https"},
+		{"spoofed leftmost entry is skipped", "10.1.2.3:1234", map[string]string{"X-Forwarded-For": "6.6.6.6, 1.2.3.4, 10.9.9.9"}, "1.2.3.4", "http"},
+		{"single trusted address", "192.0.2.1:80", map[string]string{"X-Real-IP": "1.2.3.4"}, "1.2.3.4", "http"},
+		{"forwarded header", "10.1.2.3:1234", map[string]string{"Forwarded": `for=6.6.6.6, for="[2001:db8::1]:4711";proto=https`}, "2001:db8::1", "https"},
+		{"malformed entry stops the walk", "10.1.2.3:1234", map[string]string{"X-Forwarded-For": "1.2.3.4, unknown"}, "10.1.2.3", "http"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIP, gotScheme string
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotIP, gotScheme = ClientIP(r), ClientScheme(r)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			if gotIP != tt.wantIP {
+				t.Errorf("ClientIP = %q, want %q", gotIP, tt.wantIP)
+			}
+			if gotScheme != tt.wantScheme {
+				t.Errorf("ClientScheme = %q, want %q", gotScheme, tt.wantScheme)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package secure
+
+import (
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// WWWPolicy controls how the HTTPSRedirect middleware treats the "www." subdomain.
+// WWWPolicy는 HTTPSRedirect 미들웨어가 "www." 서브도메인을 처리하는 방식을 제어합니다.
+type WWWPolicy int
+
+const (
+	// WWWAny leaves the host as requested.
+	// WWWAny는 요청된 호스트를 그대로 둡니다.
+	WWWAny WWWPolicy = iota
+	// WWWAdd redirects "example.com" to "www.example.com". Single-label hosts such as "localhost" are left alone.
+	// WWWAdd는 "example.com"을 "www.example.com"으로 리다이렉트합니다. "localhost"처럼 점이 없는 호스트는 그대로 둡니다.
+	WWWAdd
+	// WWWRemove redirects "www.example.com" to "example.com".
+	// WWWRemove는 "www.example.com"을 "example.com"으로 리다이렉트합니다.
+	WWWRemove
+)
+
+// RedirectConfig is a configuration struct for the HTTPSRedirect middleware.
+// RedirectConfig는 HTTPSRedirect 미들웨어를 위한 설정 구조체입니다.
+type RedirectConfig struct {
+	// HTTPS redirects plain HTTP requests to HTTPS. The scheme is taken from ClientScheme,
+	// so forwarding headers are honored when the RealIP middleware runs first.
+	// HTTPS는 일반 HTTP 요청을 HTTPS로 리다이렉트합니다. 스킴은 ClientScheme에서 가져오므로
+	// RealIP 미들웨어가 먼저 실행되면 전달 헤더가 반영됩니다.
+	HTTPS bool
+	// HTTPSPort is the port used in HTTPS redirect targets. Zero means the default port 443.
+	// HTTPSPort는 HTTPS 리다이렉트 대상에 사용되는 포트입니다. 0은 기본 포트 443을 의미합니다.
+	HTTPSPort int
+	// CanonicalHost, if set, redirects requests for any other host to this host (e.g. "example.com").
+	// It may include a port (e.g. "example.com:8443"), which then replaces the requested port;
+	// HTTPSPort still takes precedence when a request is redirected to HTTPS.
+	// CanonicalHost가 설정되면 다른 호스트에 대한 요청을 이 호스트(예: "example.com")로 리다이렉트합니다.
+	// 포트를 포함할 수 있으며(예: "example.com:8443"), 이 경우 요청된 포트를 대체합니다.
+	// 요청을 HTTPS로 리다이렉트할 때는 HTTPSPort가 우선합니다.
+	CanonicalHost string
+	// WWW is the www/non-www policy. It is ignored when CanonicalHost is set.
+	// WWW는 www/non-www 정책입니다. CanonicalHost가 설정되면 무시됩니다.
+	WWW WWWPolicy
+	// StatusCode is the redirect status code: 301, 302, 307 or 308. Defaults to 308 Permanent Redirect,
+	// which preserves the request method and body.
+	// StatusCode는 리다이렉트 상태 코드로 301, 302, 307, 308 중 하나입니다. 기본값은 요청 메서드와 본문을
+	// 유지하는 308 Permanent Redirect입니다.
+	StatusCode int
+	// ExemptPaths lists paths that are never redirected, such as "/.well-known/acme-challenge/" or "/healthz".
+	// An entry matches the path itself and everything below it, compared after cleaning the request path,
+	// so "/healthz/../admin" is not exempt.
+	// ExemptPaths는 "/.well-known/acme-challenge/"나 "/healthz"처럼 리다이렉트하지 않을 경로 목록입니다.
+	// 각 항목은 해당 경로 자체와 그 하위 경로 전체에 일치하며, 요청 경로를 정리한 뒤 비교하므로
+	// "/healthz/../admin"은 예외가 아닙니다.
+	ExemptPaths []string
+}
+
+// HTTPSRedirect is a middleware factory that redirects plain HTTP requests to HTTPS and
+// requests for non-canonical hosts to the canonical host, in a single redirect.
+// Combined with SecurityHeaders, this makes sure first visits end up on HTTPS where HSTS takes over.
+// HTTPSRedirect는 일반 HTTP 요청을 HTTPS로, 정식이 아닌 호스트에 대한 요청을 정식 호스트로
+// 한 번의 리다이렉트로 보내는 미들웨어 팩토리입니다.
+// SecurityHeaders와 함께 사용하면 첫 방문도 HSTS가 적용되는 HTTPS로 이동하게 됩니다.
+func HTTPSRedirect(config RedirectConfig) func(http.Handler) http.Handler {
+	status := config.StatusCode
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		status = http.StatusPermanentRedirect
+	}
+	canonicalHost, canonicalPort := splitHostPort(config.CanonicalHost)
+	exempt := make([]string, len(config.ExemptPaths))
+	copy(exempt, config.ExemptPaths)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isExemptPath(r.URL.Path, exempt) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme := ClientScheme(r)
+			host, port := splitHostPort(ClientHost(r))
+			port = explicitPort(scheme, port)
+			targetScheme, targetHost, targetPort := scheme, host, port
+
+			if canonicalHost != "" && canonicalPort != "" {
+				targetPort = canonicalPort
+			}
+			if config.HTTPS && scheme == "http" {
+				targetScheme = "https"
+				if canonicalPort == "" || config.HTTPSPort != 0 {
+					targetPort = strconv.Itoa(config.HTTPSPort)
+				}
+			}
+			targetPort = explicitPort(targetScheme, targetPort)
+			if canonicalHost != "" {
+				targetHost = canonicalHost
+			} else if host != "" && net.ParseIP(host) == nil {
+				switch config.WWW {
+				case WWWAdd:
+					if !strings.HasPrefix(host, "www.") && strings.Contains(host, ".") {
+						targetHost = "www." + host
+					}
+				case WWWRemove:
+					targetHost = strings.TrimPrefix(host, "www.")
+				}
+			}
+
+			if targetHost == "" || (targetScheme == scheme && targetHost == host && targetPort == port) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			target := targetScheme + "://" + targetHost
+			if targetPort != "" {
+				target = targetScheme + "://" + net.JoinHostPort(targetHost, targetPort)
+			}
+			http.Redirect(w, r, target+r.URL.RequestURI(), status)
+		})
+	}
+}
+
+// isExemptPath reports whether urlPath equals one of the exempt paths or lies below it.
+// isExemptPath는 urlPath가 예외 경로 중 하나와 같거나 그 하위에 있는지 여부를 반환합니다.
+func isExemptPath(urlPath string, exempt []string) bool {
+	for _, p := range exempt {
+		if pathMatches(urlPath, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathMatches reports whether the cleaned urlPath equals p or lies below it.
+// pathMatches는 정리된 urlPath가 p와 같거나 그 하위에 있는지 여부를 반환합니다.
+func pathMatches(urlPath, p string) bool {
+	urlPath = path.Clean("/" + urlPath)
+	base := strings.TrimSuffix(p, "/")
+	return urlPath == base || strings.HasPrefix(urlPath, base+"/")
+}
+
+// explicitPort returns port, or "" if it is empty, zero or the default port of scheme.
+// explicitPort는 port를 반환하며, 비어 있거나 0이거나 scheme의 기본 포트이면 ""를 반환합니다.
+func explicitPort(scheme, port string) string {
+	if port == "0" || (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		return ""
+	}
+	return port
+}
+
+// splitHostPort splits a host header value into a lower-cased host and an optional port.
+// splitHostPort는 호스트 헤더 값을 소문자 호스트와 선택적 포트로 분리합니다.
+func splitHostPort(hostport string) (host, port string) {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host, port = strings.Trim(hostport, "[]"), ""
+	}
+	return strings.ToLower(host), port
+}
@@ -0,0 +1,75 @@
+package secure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestHTTPSRedirect tests redirect targets for schemes, hosts, ports and exempt paths.
+func TestHTTPSRedirect(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  RedirectConfig
+		target  string
+		headers map[string]string
+		want    string
+	}{
+		{"http to https", RedirectConfig{HTTPS: true}, "http://example.com/a?b=1", nil, "https://example.com/a?b=1"},
+		{"https is served", RedirectConfig{HTTPS: true}, "https://example.com/a", nil, ""},
+		{"request port is dropped", RedirectConfig{HTTPS: true}, "http://example.com:8080/", nil, "https://example.com/"},
+		{"custom https port", RedirectConfig{HTTPS: true, HTTPSPort: 8443}, "http://example.com/", nil, "https://example.com:8443/"},
+		{"default https port", RedirectConfig{HTTPS: true, HTTPSPort: 443}, "http://example.com/", nil, "https://example.com/"},
+		{"forwarded https is served", RedirectConfig{HTTPS: true}, "http://example.com/", map[string]string{"X-Forwarded-Proto": "https"}, ""},
+		{"forwarded host", RedirectConfig{HTTPS: true}, "http://internal/", map[string]string{"X-Forwarded-Host": "example.com"}, "https://example.com/"},
+		{"canonical host", RedirectConfig{CanonicalHost: "Example.com"}, "https://www.example.com/a", nil, "https://example.com/a"},
+		{"canonical host is served", RedirectConfig{CanonicalHost: "example.com"}, "https://EXAMPLE.com/a", nil, ""},
+		{"canonical host and https", RedirectConfig{HTTPS: true, CanonicalHost: "example.com"}, "http://www.example.com/", nil, "https://example.com/"},
+		{"canonical host with port", RedirectConfig{CanonicalHost: "example.com:8443"}, "https://www.example.com/", nil, "https://example.com:8443/"},
+		{"canonical port is served", RedirectConfig{CanonicalHost: "example.com:8443"}, "https://example.com:8443/", nil, ""},
+		{"canonical port and https", RedirectConfig{HTTPS: true, CanonicalHost: "example.com:8443"}, "http://example.com:8080/", nil, "https://example.com:8443/"},
+		{"https port over canonical port", RedirectConfig{HTTPS: true, HTTPSPort: 9443, CanonicalHost: "example.com:8443"}, "http://example.com/", nil, "https://example.com:9443/"},
+		{"canonical default port", RedirectConfig{CanonicalHost: "example.com:443"}, "https://example.com/", nil, ""},
+		{"canonical IPv6 host", RedirectConfig{CanonicalHost: "[2001:db8::1]:8443"}, "https://example.com/", nil, "https://[2001:db8::1]:8443/"},
+		{"add www", RedirectConfig{WWW: WWWAdd}, "https://example.com/", nil, "https://www.example.com/"},
+		{"remove www", RedirectConfig{WWW: WWWRemove}, "https://www.example.com/", nil, "https://example.com/"},
+		{"www ignores IPs", RedirectConfig{WWW: WWWAdd}, "https://192.0.2.1/", nil, ""},
+		{"www ignores single-label hosts", RedirectConfig{WWW: WWWAdd}, "https://localhost:8080/", nil, ""},
+		{"exempt path", RedirectConfig{HTTPS: true, ExemptPaths: []string{"/health"}}, "http://example.com/health", nil, ""},
+		{"below exempt path", RedirectConfig{HTTPS: true, ExemptPaths: []string{"/.well-known/acme-challenge/"}}, "http://example.com/.well-known/acme-challenge/token", nil, ""},
+		{"exempt path prefix only", RedirectConfig{HTTPS: true, ExemptPaths: []string{"/health"}}, "http://example.com/healthz", nil, "https://example.com/healthz"},
+		{"exempt path traversal", RedirectConfig{HTTPS: true, ExemptPaths: []string{"/health"}}, "http://example.com/health/../admin", nil, "https://example.com/health/../admin"},
+		{"exempt path double slash", RedirectConfig{HTTPS: true, ExemptPaths: []string{"/health"}}, "http://example.com//health", nil, ""},
+		{"moved permanently", RedirectConfig{HTTPS: true, StatusCode: http.StatusMovedPermanently}, "http://example.com/a", nil, "https://example.com/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RealIP(ProxyConfig{TrustedProxies: []string{"192.0.2.0/24"}})(HTTPSRedirect(tt.config)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if tt.want == "" {
+				if rec.Code != http.StatusOK {
+					t.Errorf("status = %d, want %d (Location %q)", rec.Code, http.StatusOK, rec.Header().Get("Location"))
+				}
+				return
+			}
+			want := http.StatusPermanentRedirect
+			if tt.config.StatusCode != 0 {
+				want = tt.config.StatusCode
+			}
+			if rec.Code != want {
+				t.Errorf("status = %d, want %d", rec.Code, want)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -119,15 +119,28 @@ func GetNonce(ctx context.Context) string {
 	return val.(string)
 }
 
+// LookupNonce retrieves the nonce value from the context. It reports false if no nonce is set,
+// e.g. for handlers that may be used with or without NonceHeaders.
+// LookupNonce는 컨텍스트에서 nonce 값을 가져옵니다. Nonce가 설정되지 않았으면 false를 반환하며,
+// NonceHeaders와 함께 또는 없이 사용될 수 있는 핸들러를 위한 것입니다.
+func LookupNonce(ctx context.Context) (string, bool) {
+	nonce, ok := ctx.Value(nonceContextKey{}).(string)
+	return nonce, ok
+}
+
 // SecurityHeaders is a middleware that sets several security-related HTTP headers to the response.
+// Strict-Transport-Security is only sent over HTTPS, as reported by ClientScheme, since browsers ignore it over HTTP.
 // SecurityHeaders 미들웨어는 여러 보안 관련 HTTP 헤더들을 응답에 설정합니다.
+// 브라우저는 HTTP에서 Strict-Transport-Security를 무시하므로, ClientScheme이 HTTPS를 보고할 때만 전송합니다.
 func SecurityHeaders(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-XSS-Protection", "1; mode=block")
 		w.Header().Set("X-Content-Type-Options", "nosniff")
 		w.Header().Set("X-Frame-Options", "SAMEORIGIN")
 		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
-		w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		if ClientScheme(r) == "https" {
+			w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		}
 		next.ServeHTTP(w, r)
 	})
 }
@@ -1,6 +1,9 @@
 package secure
 
 import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -25,6 +28,45 @@ func TestCryptoRandNonceBasic(t *testing.T) {
 	// Additional checks can be added, e.g., base64 validity
 }
 
+// TestSecurityHeadersHSTS tests that Strict-Transport-Security is only sent for HTTPS clients.
+func TestSecurityHeadersHSTS(t *testing.T) {
+	h := RealIP(ProxyConfig{TrustedProxies: []string{"192.0.2.0/24"}})(SecurityHeaders(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	tests := []struct {
+		name    string
+		tls     bool
+		remote  string
+		headers map[string]string
+		want    bool
+	}{
+		{"plain http", false, "198.51.100.1:1234", nil, false},
+		{"direct https", true, "198.51.100.1:1234", nil, true},
+		{"https from trusted proxy", false, "192.0.2.1:1234", map[string]string{"X-Forwarded-Proto": "https"}, true},
+		{"https from untrusted peer", false, "198.51.100.1:1234", map[string]string{"X-Forwarded-Proto": "https"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if got := rec.Header().Get("Strict-Transport-Security") != ""; got != tt.want {
+				t.Errorf("HSTS sent = %v, want %v", got, tt.want)
+			}
+			if rec.Header().Get("X-Content-Type-Options") != "nosniff" {
+				t.Errorf("missing other headers: %v", rec.Header())
+			}
+		})
+	}
+}
+
 // To run memory profiling:
 // 1. go test -bench=BenchmarkCryptoRandNonce -memprofile=mem.out
 // 2. go tool pprof mem.out